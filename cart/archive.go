@@ -0,0 +1,119 @@
+package cart
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// Magic bytes at the start of the supported archive formats.
+var (
+	zipMagic      = []byte("PK\x03\x04")
+	zipEmptyMagic = []byte("PK\x05\x06")
+	gzipMagic     = []byte{0x1F, 0x8B}
+	sevenZipMagic = []byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C}
+)
+
+// romExts are the extensions of the ROM files extracted from zip archives.
+var romExts = []string{".gb", ".gbc", ".sgb"}
+
+// EntryChooser chooses which ROM to load from an archive containing
+// several ROMs. It receives the names of the ROM entries, and returns
+// the index of the chosen one.
+type EntryChooser func(names []string) (int, error)
+
+// firstEntry is the default EntryChooser, which chooses the first ROM.
+func firstEntry(names []string) (int, error) {
+	return 0, nil
+}
+
+// unarchive extracts the ROM from data, if data is a zip or gzip archive.
+// Otherwise, data is returned as is.
+func unarchive(data []byte, choose EntryChooser) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, zipMagic) || bytes.HasPrefix(data, zipEmptyMagic):
+		return unzip(data, choose)
+	case bytes.HasPrefix(data, gzipMagic):
+		return gunzip(data)
+	case bytes.HasPrefix(data, sevenZipMagic):
+		return nil, errors.E("7z archives are not supported", errors.Cart)
+	default:
+		return data, nil
+	}
+}
+
+// unzip extracts a ROM from a zip archive.
+// If the archive contains several ROMs, choose selects one of them.
+func unzip(data []byte, choose EntryChooser) ([]byte, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.E("read zip archive failed", err, errors.Cart)
+	}
+
+	var entries []*zip.File
+	var names []string
+	for _, f := range r.File {
+		if isROMName(f.Name) {
+			entries = append(entries, f)
+			names = append(names, f.Name)
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.E("zip archive contains no rom", errors.Cart)
+	}
+
+	i := 0
+	if len(entries) > 1 {
+		if i, err = choose(names); err != nil {
+			return nil, errors.E("choose zip entry failed", err, errors.Cart)
+		}
+		if i < 0 || i >= len(entries) {
+			return nil, errors.E(fmt.Sprintf("invalid zip entry %d", i), errors.Cart)
+		}
+	}
+
+	f, err := entries[i].Open()
+	if err != nil {
+		return nil, errors.E("open zip entry failed", err, errors.Cart)
+	}
+	defer f.Close()
+
+	rom, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, errors.E("read zip entry failed", err, errors.Cart)
+	}
+	return rom, nil
+}
+
+// gunzip decompresses a gzip archive.
+func gunzip(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.E("read gzip archive failed", err, errors.Cart)
+	}
+	defer r.Close()
+
+	rom, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.E("decompress gzip archive failed", err, errors.Cart)
+	}
+	return rom, nil
+}
+
+// isROMName checks if the name of an archive entry has a ROM extension.
+func isROMName(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, e := range romExts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
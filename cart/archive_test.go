@@ -0,0 +1,135 @@
+package cart
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+// testROM returns a ROM only controller with the given title.
+func testROM(title string) []byte {
+	bytes := make([]byte, romCtrROMEnd+1)
+	copyAt([]byte(title), bytes, titleStart)
+	return bytes
+}
+
+// zipROMs creates a zip archive containing the given files.
+func zipROMs(t *testing.T, files map[string][]byte, order []string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range order {
+		f, err := w.Create(name)
+		assert.Err(t, err, false)
+		f.Write(files[name])
+	}
+	assert.Err(t, w.Close(), false)
+
+	return buf.Bytes()
+}
+
+func TestRead(t *testing.T) {
+	t.Run("ROM", func(t *testing.T) {
+		c, err := Read(bytes.NewReader(testROM("RAW")), WithValidation(ValidateIgnore))
+		assert.Err(t, err, false)
+		assert.Equal(t, c.Title(), "RAW")
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		w.Write(testROM("GZIP"))
+		w.Close()
+
+		c, err := Read(&buf, WithValidation(ValidateIgnore))
+		assert.Err(t, err, false)
+		assert.Equal(t, c.Title(), "GZIP")
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		data := zipROMs(t, map[string][]byte{
+			"readme.txt": []byte("hello"),
+			"game.GBC":   testROM("ZIP"),
+		}, []string{"readme.txt", "game.GBC"})
+
+		c, err := Read(bytes.NewReader(data), WithValidation(ValidateIgnore))
+		assert.Err(t, err, false)
+		assert.Equal(t, c.Title(), "ZIP")
+	})
+
+	files := map[string][]byte{
+		"a.gb":  testROM("FIRST"),
+		"b.sgb": testROM("SECOND"),
+	}
+	order := []string{"a.gb", "b.sgb"}
+
+	t.Run("zip, first entry by default", func(t *testing.T) {
+		c, err := Read(bytes.NewReader(zipROMs(t, files, order)), WithValidation(ValidateIgnore))
+		assert.Err(t, err, false)
+		assert.Equal(t, c.Title(), "FIRST")
+	})
+
+	t.Run("zip, chosen entry", func(t *testing.T) {
+		var got []string
+		choose := func(names []string) (int, error) {
+			got = names
+			return 1, nil
+		}
+
+		c, err := Read(bytes.NewReader(zipROMs(t, files, order)), WithValidation(ValidateIgnore), WithEntry(choose))
+		assert.Err(t, err, false)
+		assert.Equal(t, c.Title(), "SECOND")
+		assert.Equal(t, got, order)
+	})
+
+	t.Run("zip, invalid entry", func(t *testing.T) {
+		choose := func(names []string) (int, error) { return 2, nil }
+
+		_, err := Read(bytes.NewReader(zipROMs(t, files, order)), WithEntry(choose))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("zip, chooser error", func(t *testing.T) {
+		choose := func(names []string) (int, error) { return 0, fmt.Errorf("cancelled") }
+
+		_, err := Read(bytes.NewReader(zipROMs(t, files, order)), WithEntry(choose))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("zip without ROMs", func(t *testing.T) {
+		data := zipROMs(t, map[string][]byte{"readme.txt": []byte("hello")}, []string{"readme.txt"})
+
+		_, err := Read(bytes.NewReader(data))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("7z", func(t *testing.T) {
+		_, err := Read(bytes.NewReader(append(sevenZipMagic, make([]byte, 0x8000)...)))
+		assert.Err(t, err, true)
+	})
+}
+
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gameboy-cart")
+	assert.Err(t, err, false)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "game.zip")
+	data := zipROMs(t, map[string][]byte{"game.gb": testROM("OPEN")}, []string{"game.gb"})
+	assert.Err(t, ioutil.WriteFile(p, data, 0644), false)
+
+	c, err := Open(p, WithValidation(ValidateIgnore))
+	assert.Err(t, err, false)
+	assert.Equal(t, c.Title(), "OPEN")
+
+	_, err = Open(filepath.Join(dir, "missing.gb"))
+	assert.Err(t, err, true)
+}
@@ -3,10 +3,12 @@ package cart
 import (
 	"github.com/lucactt/gameboy/mem"
 	"github.com/lucactt/gameboy/util/errors"
+	"github.com/rs/zerolog/log"
 )
 
 // Addresses of the info contained in the header.
 const (
+	logoStart    uint16 = 0x0104
 	titleStart   uint16 = 0x0134
 	titleEnd     uint16 = 0x0143
 	cartTypeFlag uint16 = 0x0147
@@ -15,6 +17,12 @@ const (
 	headerEnd    uint16 = 0x014F
 )
 
+// Addresses of the external RAM.
+const (
+	ramStart uint16 = 0xA000
+	ramEnd   uint16 = 0xBFFF
+)
+
 // Byte values used to identify the number of RAM banks.
 const (
 	valueRAMBank1  byte = 0x02
@@ -23,6 +31,14 @@ const (
 	valueRAMBank8  byte = 0x05
 )
 
+// nintendoLogo is the logo bitmap contained in the header of
+// every licensed cartridge.
+var nintendoLogo = []byte{
+	0xCE, 0xED, 0x66, 0x66, 0xCC, 0x0D, 0x00, 0x0B, 0x03, 0x73, 0x00, 0x83, 0x00, 0x0C, 0x00, 0x0D,
+	0x00, 0x08, 0x11, 0x1F, 0x88, 0x89, 0x00, 0x0E, 0xDC, 0xCC, 0x6E, 0xE6, 0xDD, 0xDD, 0xD9, 0x99,
+	0xBB, 0xBB, 0x67, 0x63, 0x6E, 0x0E, 0xEC, 0xCC, 0xDD, 0xDC, 0x99, 0x9F, 0xBB, 0xB9, 0x33, 0x3E,
+}
+
 // Size of the ROM and RAM banks.
 const (
 	romBankSize int = 16384
@@ -35,33 +51,112 @@ type Controller interface {
 	mem.Mem
 }
 
+// romBanker is implemented by the controllers that can
+// switch the ROM bank mapped to 0x4000-0x7FFF.
+type romBanker interface {
+	ROMBank() int
+}
+
+// rumbler is implemented by the controllers that
+// can drive a rumble motor.
+type rumbler interface {
+	SetRumble(f RumbleFunc)
+}
+
+// ramEnabler is implemented by the controllers that
+// can disable the RAM, ignoring its reads and writes.
+type ramEnabler interface {
+	RAMEnabled() bool
+}
+
 // Cart represents a Gameboy cartridge.
 type Cart struct {
-	title string
-	ctr   Controller
+	header Header
+	ctr    Controller
+	ram    []byte
+	rtc    *RTC
+	// ramWrites counts the writes that reached the external RAM
+	// or the clock, and is used to detect when the RAM must be saved.
+	ramWrites uint64
 }
 
 // NewCart creates a new cartridge from the given ROM.
 // It will return an error if the ROM is an invalid or unsupported cartridge.
-func NewCart(rom []byte) (*Cart, error) {
-	if len(rom) < int(headerEnd) {
-		return nil, errors.E("rom size insufficient to contain header", errors.Cart)
+//
+// The checksums and the logo in the header are validated as specified by the
+// options, by default logging a warning if they are invalid.
+func NewCart(rom []byte, opts ...Option) (*Cart, error) {
+	o := newOptions(opts)
+
+	header, err := ParseHeader(rom)
+	if err != nil {
+		return nil, errors.E("parse header failed", err, errors.Cart)
 	}
 
-	title := getString(rom, titleStart, titleEnd)
+	if err := validate(rom, o.validation); err != nil {
+		return nil, errors.E("validate header failed", err, errors.Cart)
+	}
 
-	ram := make([]byte, ramBanks(rom))
-	ctr, err := controller(rom, ram)
+	ram := make([]byte, header.RAMSize)
+	var rtc *RTC
+	if header.Type.HasTimer() {
+		rtc = NewRTC(o.now)
+	}
+
+	ctr, err := controller(header.Type, rom, ram, rtc)
 	if err != nil {
 		return nil, errors.E("create controller failed", err, errors.Cart)
 	}
 
-	return &Cart{title, ctr}, nil
+	// The MBC2 RAM is built into the controller.
+	if mbc2, ok := ctr.(*MBC2); ok {
+		ram = mbc2.ram
+	}
+
+	return &Cart{header: header, ctr: ctr, ram: ram, rtc: rtc}, nil
+}
+
+// validate verifies the ROM, using the given validation mode
+// to handle mismatches.
+func validate(rom []byte, v Validation) error {
+	if v == ValidateIgnore {
+		return nil
+	}
+
+	for _, err := range Verify(rom) {
+		if v == ValidateReject {
+			return err
+		}
+		log.Warn().Err(err).Msg("invalid cartridge header")
+	}
+	return nil
+}
+
+// Header returns the header of the cartridge.
+func (c *Cart) Header() Header {
+	return c.header
 }
 
 // Title returns the title of the cartridge.
 func (c *Cart) Title() string {
-	return c.title
+	return c.header.Title
+}
+
+// ROMBank returns the ROM bank currently mapped to 0x4000-0x7FFF.
+func (c *Cart) ROMBank() int {
+	if b, ok := c.ctr.(romBanker); ok {
+		return b.ROMBank()
+	}
+	return 1
+}
+
+// SetRumble sets the function called when the rumble motor
+// of the cartridge is turned on or off.
+// It does nothing if the cartridge has no rumble motor.
+func (c *Cart) SetRumble(f RumbleFunc) {
+	if r, ok := c.ctr.(rumbler); ok {
+		r.SetRumble(f)
+	}
 }
 
 // GetByte returns the byte at the given address.
@@ -81,9 +176,26 @@ func (c *Cart) SetByte(addr uint16, value byte) error {
 	if err := c.ctr.SetByte(addr, value); err != nil {
 		return errors.E("get byte from cartridge failed", err, errors.Cart)
 	}
+
+	if addr >= ramStart && addr <= ramEnd && c.ramWritable() {
+		c.ramWrites++
+	}
 	return nil
 }
 
+// ramWritable checks if the writes to the external RAM addresses
+// reach the RAM or the clock, which requires them to exist and,
+// if the controller can disable them, to be enabled.
+func (c *Cart) ramWritable() bool {
+	if len(c.ram) == 0 && c.rtc == nil {
+		return false
+	}
+	if e, ok := c.ctr.(ramEnabler); ok {
+		return e.RAMEnabled()
+	}
+	return true
+}
+
 // Accepts checks if an address is included in the cartridge.
 func (c *Cart) Accepts(addr uint16) bool {
 	return c.ctr.Accepts(addr)
@@ -20,10 +20,88 @@ func TestNewCart(t *testing.T) {
 		assert.Err(t, err, true)
 	})
 
+	t.Run("invalid checksum rejected", func(t *testing.T) {
+		bytes := validROM()
+		bytes[headerChecksum]++
+
+		_, err := NewCart(bytes, WithValidation(ValidateReject))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("valid checksum accepted", func(t *testing.T) {
+		_, err := NewCart(validROM(), WithValidation(ValidateReject))
+		assert.Err(t, err, false)
+	})
+
+	t.Run("invalid checksum ignored", func(t *testing.T) {
+		bytes := validROM()
+		bytes[headerChecksum]++
+
+		_, err := NewCart(bytes, WithValidation(ValidateIgnore))
+		assert.Err(t, err, false)
+	})
+
 	t.Run("valid controller", func(t *testing.T) {
 		_, err := NewCart(make([]byte, romCtrROMEnd+1))
 		assert.Err(t, err, false)
 	})
+
+	t.Run("mbc1 controller", func(t *testing.T) {
+		bytes := make([]byte, romCtrROMEnd+1)
+		bytes[cartTypeFlag] = 0x03
+		bytes[ramSizeFlag] = valueRAMBank4
+
+		r, err := NewCart(bytes)
+		assert.Err(t, err, false)
+
+		_, ok := r.ctr.(*MBC1)
+		assert.Equal(t, ok, true)
+	})
+
+	t.Run("mbc2 controller", func(t *testing.T) {
+		bytes := make([]byte, romCtrROMEnd+1)
+		bytes[cartTypeFlag] = 0x06
+
+		r, err := NewCart(bytes)
+		assert.Err(t, err, false)
+
+		_, ok := r.ctr.(*MBC2)
+		assert.Equal(t, ok, true)
+	})
+
+	t.Run("mbc3 controller", func(t *testing.T) {
+		bytes := make([]byte, romCtrROMEnd+1)
+		bytes[cartTypeFlag] = 0x10
+
+		r, err := NewCart(bytes)
+		assert.Err(t, err, false)
+
+		ctr, ok := r.ctr.(*MBC3)
+		assert.Equal(t, ok, true)
+		assert.Equal(t, ctr.rtc != nil, true)
+	})
+
+	t.Run("mbc5 controller", func(t *testing.T) {
+		bytes := make([]byte, romCtrROMEnd+1)
+		bytes[cartTypeFlag] = 0x1E
+
+		r, err := NewCart(bytes)
+		assert.Err(t, err, false)
+
+		ctr, ok := r.ctr.(*MBC5)
+		assert.Equal(t, ok, true)
+		assert.Equal(t, ctr.hasRumble, true)
+	})
+
+	t.Run("mbc1m controller", func(t *testing.T) {
+		bytes := make([]byte, mbc1MBanks*romBankSize)
+		bytes[cartTypeFlag] = 0x01
+		copy(bytes[mbc1MGameBank*romBankSize+int(logoStart):], nintendoLogo)
+
+		r, err := NewCart(bytes)
+		assert.Err(t, err, false)
+		assert.Equal(t, r.ctr.(*MBC1).isMulticart, true)
+	})
 }
 
 func TestCart_GetByte(t *testing.T) {
@@ -71,6 +149,39 @@ func TestCart_SetByte(t *testing.T) {
 		err := r.SetByte(0xFFFF, 0x11)
 		assert.Err(t, err, true)
 	})
+
+	t.Run("ram writes", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			cartType CartType
+			ramSize  byte
+			enable   byte
+			want     uint64
+		}{
+			{"rom ram", TypeROMRAMBattery, valueRAMBank1, 0x00, 1},
+			{"mbc1 ram enabled", TypeMBC1RAMBattery, valueRAMBank1, 0x0A, 1},
+			{"mbc1 ram disabled", TypeMBC1RAMBattery, valueRAMBank1, 0x00, 0},
+			{"mbc1 no ram", TypeMBC1, 0x00, 0x0A, 0},
+			{"mbc3 clock enabled", TypeMBC3TimerBattery, 0x00, 0x0A, 1},
+			{"mbc3 clock disabled", TypeMBC3TimerBattery, 0x00, 0x00, 0},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				bytes := make([]byte, romCtrROMEnd+1)
+				bytes[cartTypeFlag] = byte(tt.cartType)
+				bytes[ramSizeFlag] = tt.ramSize
+
+				c, err := NewCart(bytes, WithValidation(ValidateIgnore))
+				assert.Err(t, err, false)
+
+				c.SetByte(0x0000, tt.enable)
+				c.SetByte(0x4000, 0x08)
+				c.SetByte(romCtrRAMStart, 0x11)
+				assert.Equal(t, c.ramWrites, tt.want)
+			})
+		}
+	})
 }
 
 func TestCart_Accepts(t *testing.T) {
@@ -88,3 +199,41 @@ func TestCart_Title(t *testing.T) {
 	r, _ := NewCart(bytes)
 	assert.Equal(t, r.Title(), "TEST")
 }
+
+func TestCart_Header(t *testing.T) {
+	bytes := make([]byte, romCtrROMEnd+1)
+	bytes[cartTypeFlag] = byte(TypeROMRAM)
+	bytes[ramSizeFlag] = valueRAM2KB
+
+	r, _ := NewCart(bytes)
+	assert.Equal(t, r.Header().Type, TypeROMRAM)
+	assert.Equal(t, r.Header().RAMSize, 2048)
+}
+
+func TestCart_ROMBank(t *testing.T) {
+	t.Run("no banking", func(t *testing.T) {
+		r, _ := NewCart(make([]byte, romCtrROMEnd+1))
+		assert.Equal(t, r.ROMBank(), 1)
+	})
+
+	t.Run("switched bank", func(t *testing.T) {
+		ctr, _ := NewMBC1(make([]byte, 4*romBankSize), make([]byte, 0))
+		r := &Cart{ctr: ctr}
+		r.SetByte(mbc1ROMBankStart, 0x03)
+
+		assert.Equal(t, r.ROMBank(), 3)
+	})
+}
+
+func TestCart_SetRumble(t *testing.T) {
+	bytes := make([]byte, romCtrROMEnd+1)
+	bytes[cartTypeFlag] = 0x1C
+
+	r, _ := NewCart(bytes)
+
+	got := false
+	r.SetRumble(func(on bool) { got = on })
+	r.SetByte(mbc5RAMBankStart, mbc5RumbleBit)
+
+	assert.Equal(t, got, true)
+}
@@ -0,0 +1,67 @@
+package cart
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// Codes of the errors returned by Verify.
+const (
+	InvalidLogo errors.ErrCode = iota + 1
+	InvalidHeaderChecksum
+	InvalidGlobalChecksum
+)
+
+// Addresses of the header bytes included in the header checksum.
+const (
+	headerChecksumStart uint16 = 0x0134
+	headerChecksumEnd   uint16 = 0x014C
+)
+
+// HeaderChecksum computes the checksum of the header bytes at 0x0134-0x014C,
+// which is verified by the boot ROM.
+func HeaderChecksum(rom []byte) byte {
+	var sum byte
+	for _, b := range rom[headerChecksumStart : headerChecksumEnd+1] {
+		sum = sum - b - 1
+	}
+	return sum
+}
+
+// GlobalChecksum computes the sum of all the ROM bytes,
+// except the two bytes of the global checksum.
+func GlobalChecksum(rom []byte) uint16 {
+	var sum uint16
+	for i, b := range rom {
+		if i != int(globalChecksum) && i != int(globalChecksum+1) {
+			sum += uint16(b)
+		}
+	}
+	return sum
+}
+
+// Verify checks the Nintendo logo, the header checksum and the global checksum
+// of the ROM, and returns an error for each mismatch.
+// The ROM must be large enough to contain the header.
+func Verify(rom []byte) []error {
+	var errs []error
+
+	if !bytes.Equal(rom[logoStart:int(logoStart)+len(nintendoLogo)], nintendoLogo) {
+		errs = append(errs, errors.E("invalid nintendo logo", InvalidLogo, errors.Cart))
+	}
+
+	if got, want := HeaderChecksum(rom), rom[headerChecksum]; got != want {
+		msg := fmt.Sprintf("header checksum is 0x%02X, want 0x%02X", got, want)
+		errs = append(errs, errors.E(msg, InvalidHeaderChecksum, errors.Cart))
+	}
+
+	want := uint16(rom[globalChecksum])<<8 | uint16(rom[globalChecksum+1])
+	if got := GlobalChecksum(rom); got != want {
+		msg := fmt.Sprintf("global checksum is 0x%04X, want 0x%04X", got, want)
+		errs = append(errs, errors.E(msg, InvalidGlobalChecksum, errors.Cart))
+	}
+
+	return errs
+}
@@ -0,0 +1,66 @@
+package cart
+
+import (
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// validROM returns a ROM with a valid logo and checksums.
+func validROM() []byte {
+	bytes := make([]byte, romCtrROMEnd+1)
+	copy(bytes[logoStart:], nintendoLogo)
+	copyAt([]byte("TEST"), bytes, titleStart)
+	bytes[0x7FFF] = 0x11
+
+	bytes[headerChecksum] = HeaderChecksum(bytes)
+	sum := GlobalChecksum(bytes)
+	bytes[globalChecksum] = byte(sum >> 8)
+	bytes[globalChecksum+1] = byte(sum)
+	return bytes
+}
+
+func TestHeaderChecksum(t *testing.T) {
+	bytes := make([]byte, romCtrROMEnd+1)
+	assert.Equal(t, HeaderChecksum(bytes), byte(0xE7))
+
+	bytes[headerChecksumStart] = 0x01
+	assert.Equal(t, HeaderChecksum(bytes), byte(0xE6))
+}
+
+func TestGlobalChecksum(t *testing.T) {
+	bytes := make([]byte, romCtrROMEnd+1)
+	bytes[0x0000] = 0xFF
+	bytes[0x7FFF] = 0x02
+	bytes[globalChecksum] = 0x12
+	bytes[globalChecksum+1] = 0x34
+
+	assert.Equal(t, GlobalChecksum(bytes), uint16(0x0101))
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(rom []byte)
+		want   []errors.ErrCode
+	}{
+		{"valid", func(rom []byte) {}, nil},
+		{"invalid logo", func(rom []byte) { rom[logoStart] = 0x00 }, []errors.ErrCode{InvalidLogo, InvalidGlobalChecksum}},
+		{"invalid header checksum", func(rom []byte) { rom[headerChecksum]++; rom[0x7FFF]-- }, []errors.ErrCode{InvalidHeaderChecksum}},
+		{"invalid global checksum", func(rom []byte) { rom[0x7FFF] = 0x00 }, []errors.ErrCode{InvalidGlobalChecksum}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rom := validROM()
+			tt.modify(rom)
+
+			var got []errors.ErrCode
+			for _, err := range Verify(rom) {
+				got = append(got, err.(*errors.Error).Code)
+			}
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package cart
+
+import (
+	"fmt"
+
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// Addresses of the header fields not used by the controllers.
+const (
+	manufacturerStart uint16 = 0x013F
+	manufacturerEnd   uint16 = 0x0143
+	cgbFlag           uint16 = 0x0143
+	newLicenseeStart  uint16 = 0x0144
+	newLicenseeEnd    uint16 = 0x0146
+	sgbFlag           uint16 = 0x0146
+	destinationFlag   uint16 = 0x014A
+	oldLicenseeFlag   uint16 = 0x014B
+	versionFlag       uint16 = 0x014C
+	headerChecksum    uint16 = 0x014D
+	globalChecksum    uint16 = 0x014E
+)
+
+// Values of the header flags.
+const (
+	// cgbFlagBit is set in the CGB flag if the cartridge supports the CGB functions.
+	cgbFlagBit byte = 0x80
+	// useNewLicensee is the value of the old licensee code
+	// which signals that the new licensee code is used.
+	useNewLicensee byte = 0x33
+	// valueRAM2KB is the RAM size flag value of a 2 KB RAM.
+	valueRAM2KB byte = 0x01
+)
+
+// CartType is the type of a cartridge, which identifies its
+// controller and its additional hardware.
+type CartType byte
+
+// Cartridge types.
+const (
+	TypeROM                  CartType = 0x00
+	TypeMBC1                 CartType = 0x01
+	TypeMBC1RAM              CartType = 0x02
+	TypeMBC1RAMBattery       CartType = 0x03
+	TypeMBC2                 CartType = 0x05
+	TypeMBC2Battery          CartType = 0x06
+	TypeROMRAM               CartType = 0x08
+	TypeROMRAMBattery        CartType = 0x09
+	TypeMMM01                CartType = 0x0B
+	TypeMMM01RAM             CartType = 0x0C
+	TypeMMM01RAMBattery      CartType = 0x0D
+	TypeMBC3TimerBattery     CartType = 0x0F
+	TypeMBC3TimerRAMBattery  CartType = 0x10
+	TypeMBC3                 CartType = 0x11
+	TypeMBC3RAM              CartType = 0x12
+	TypeMBC3RAMBattery       CartType = 0x13
+	TypeMBC5                 CartType = 0x19
+	TypeMBC5RAM              CartType = 0x1A
+	TypeMBC5RAMBattery       CartType = 0x1B
+	TypeMBC5Rumble           CartType = 0x1C
+	TypeMBC5RumbleRAM        CartType = 0x1D
+	TypeMBC5RumbleRAMBattery CartType = 0x1E
+	TypeMBC6                 CartType = 0x20
+	TypeMBC7                 CartType = 0x22
+	TypePocketCamera         CartType = 0xFC
+	TypeTAMA5                CartType = 0xFD
+	TypeHuC3                 CartType = 0xFE
+	TypeHuC1RAMBattery       CartType = 0xFF
+)
+
+var cartTypeNames = map[CartType]string{
+	TypeROM:                  "ROM",
+	TypeMBC1:                 "MBC1",
+	TypeMBC1RAM:              "MBC1+RAM",
+	TypeMBC1RAMBattery:       "MBC1+RAM+BATTERY",
+	TypeMBC2:                 "MBC2",
+	TypeMBC2Battery:          "MBC2+BATTERY",
+	TypeROMRAM:               "ROM+RAM",
+	TypeROMRAMBattery:        "ROM+RAM+BATTERY",
+	TypeMMM01:                "MMM01",
+	TypeMMM01RAM:             "MMM01+RAM",
+	TypeMMM01RAMBattery:      "MMM01+RAM+BATTERY",
+	TypeMBC3TimerBattery:     "MBC3+TIMER+BATTERY",
+	TypeMBC3TimerRAMBattery:  "MBC3+TIMER+RAM+BATTERY",
+	TypeMBC3:                 "MBC3",
+	TypeMBC3RAM:              "MBC3+RAM",
+	TypeMBC3RAMBattery:       "MBC3+RAM+BATTERY",
+	TypeMBC5:                 "MBC5",
+	TypeMBC5RAM:              "MBC5+RAM",
+	TypeMBC5RAMBattery:       "MBC5+RAM+BATTERY",
+	TypeMBC5Rumble:           "MBC5+RUMBLE",
+	TypeMBC5RumbleRAM:        "MBC5+RUMBLE+RAM",
+	TypeMBC5RumbleRAMBattery: "MBC5+RUMBLE+RAM+BATTERY",
+	TypeMBC6:                 "MBC6",
+	TypeMBC7:                 "MBC7+SENSOR+RUMBLE+RAM+BATTERY",
+	TypePocketCamera:         "POCKET CAMERA",
+	TypeTAMA5:                "BANDAI TAMA5",
+	TypeHuC3:                 "HuC3",
+	TypeHuC1RAMBattery:       "HuC1+RAM+BATTERY",
+}
+
+// String returns the name of the cartridge type.
+func (t CartType) String() string {
+	if name, ok := cartTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown (0x%02X)", byte(t))
+}
+
+// HasBattery returns true if the cartridge type has a battery,
+// which keeps the content of the RAM and the clock when turned off.
+func (t CartType) HasBattery() bool {
+	switch t {
+	case TypeMBC1RAMBattery, TypeMBC2Battery, TypeROMRAMBattery, TypeMMM01RAMBattery,
+		TypeMBC3TimerBattery, TypeMBC3TimerRAMBattery, TypeMBC3RAMBattery,
+		TypeMBC5RAMBattery, TypeMBC5RumbleRAMBattery, TypeMBC7,
+		TypePocketCamera, TypeHuC3, TypeHuC1RAMBattery:
+		return true
+	default:
+		return false
+	}
+}
+
+// HasTimer returns true if the cartridge type has a real time clock.
+func (t CartType) HasTimer() bool {
+	return t == TypeMBC3TimerBattery || t == TypeMBC3TimerRAMBattery
+}
+
+// Header contains the information stored in the cartridge header,
+// at 0x0100-0x014F.
+type Header struct {
+	Title string
+	// ManufacturerCode is set only by newer cartridges,
+	// which have a shorter title.
+	ManufacturerCode string
+	CGBFlag          byte
+	SGBFlag          byte
+	Type             CartType
+	// ROMSize is the size of the ROM in bytes, or 0 if the flag is invalid.
+	ROMSize int
+	// RAMSize is the size of the external RAM in bytes.
+	RAMSize     int
+	Destination byte
+	OldLicensee byte
+	// NewLicensee is used only if OldLicensee is 0x33.
+	NewLicensee    string
+	Version        byte
+	HeaderChecksum byte
+	GlobalChecksum uint16
+}
+
+// ParseHeader reads the header of the given ROM.
+// It will return an error if the ROM is too small to contain the header.
+func ParseHeader(rom []byte) (Header, error) {
+	if len(rom) <= int(headerEnd) {
+		return Header{}, errors.E("rom size insufficient to contain header", errors.Cart)
+	}
+
+	h := Header{
+		CGBFlag:        rom[cgbFlag],
+		SGBFlag:        rom[sgbFlag],
+		Type:           CartType(rom[cartTypeFlag]),
+		ROMSize:        romSize(rom[romSizeFlag]),
+		RAMSize:        ramSize(rom[ramSizeFlag]),
+		Destination:    rom[destinationFlag],
+		OldLicensee:    rom[oldLicenseeFlag],
+		NewLicensee:    string(rom[newLicenseeStart:newLicenseeEnd]),
+		Version:        rom[versionFlag],
+		HeaderChecksum: rom[headerChecksum],
+		GlobalChecksum: uint16(rom[globalChecksum])<<8 | uint16(rom[globalChecksum+1]),
+	}
+
+	switch {
+	// Cartridges with the new licensee code and CGB support have an
+	// 11 characters title, followed by the manufacturer code.
+	case h.CGBFlag&cgbFlagBit != 0 && h.OldLicensee == useNewLicensee:
+		h.Title = getString(rom, titleStart, manufacturerStart)
+		h.ManufacturerCode = getString(rom, manufacturerStart, manufacturerEnd)
+	// Older CGB cartridges use the last title character as the CGB flag.
+	case h.CGBFlag&cgbFlagBit != 0:
+		h.Title = getString(rom, titleStart, cgbFlag)
+	default:
+		h.Title = getString(rom, titleStart, titleEnd+1)
+	}
+
+	return h, nil
+}
+
+// romSize returns the size in bytes of the ROM with the given size flag.
+func romSize(flag byte) int {
+	switch {
+	case flag <= 0x08:
+		return 2 * romBankSize << flag
+	case flag == 0x52:
+		return 72 * romBankSize
+	case flag == 0x53:
+		return 80 * romBankSize
+	case flag == 0x54:
+		return 96 * romBankSize
+	default:
+		return 0
+	}
+}
+
+// ramSize returns the size in bytes of the RAM with the given size flag.
+func ramSize(flag byte) int {
+	switch flag {
+	case valueRAM2KB:
+		return ramBankSize / 4
+	case valueRAMBank1:
+		return ramBankSize
+	case valueRAMBank4:
+		return 4 * ramBankSize
+	case valueRAMBank16:
+		return 16 * ramBankSize
+	case valueRAMBank8:
+		return 8 * ramBankSize
+	default:
+		return 0
+	}
+}
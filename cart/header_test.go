@@ -0,0 +1,94 @@
+package cart
+
+import (
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+func TestParseHeader(t *testing.T) {
+	t.Run("rom too small", func(t *testing.T) {
+		_, err := ParseHeader(make([]byte, headerEnd))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("all fields", func(t *testing.T) {
+		bytes := make([]byte, romCtrROMEnd+1)
+		copyAt([]byte("POKEMON RED"), bytes, titleStart)
+		copyAt([]byte("01"), bytes, newLicenseeStart)
+		bytes[sgbFlag] = 0x03
+		bytes[cartTypeFlag] = 0x13
+		bytes[romSizeFlag] = 0x05
+		bytes[ramSizeFlag] = valueRAMBank4
+		bytes[destinationFlag] = 0x01
+		bytes[oldLicenseeFlag] = useNewLicensee
+		bytes[versionFlag] = 0x01
+		bytes[headerChecksum] = 0x20
+		bytes[globalChecksum] = 0x91
+		bytes[globalChecksum+1] = 0xE6
+
+		got, err := ParseHeader(bytes)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, Header{
+			Title:          "POKEMON RED",
+			SGBFlag:        0x03,
+			Type:           TypeMBC3RAMBattery,
+			ROMSize:        64 * romBankSize,
+			RAMSize:        4 * ramBankSize,
+			Destination:    0x01,
+			OldLicensee:    useNewLicensee,
+			NewLicensee:    "01",
+			Version:        0x01,
+			HeaderChecksum: 0x20,
+			GlobalChecksum: 0x91E6,
+		})
+	})
+
+	tests := []struct {
+		name             string
+		title            string
+		cgb              byte
+		licensee         byte
+		wantTitle        string
+		wantManufacturer string
+	}{
+		{"DMG title", "ABCDEFGHIJKLMNOP", 0x00, useNewLicensee, "ABCDEFGHIJKLMNOP", ""},
+		{"old CGB title", "ABCDEFGHIJKLMNO", 0x80, 0x01, "ABCDEFGHIJKLMNO", ""},
+		{"new CGB title", "ABCDEFGHIJKAXYZ", 0xC0, useNewLicensee, "ABCDEFGHIJK", "AXYZ"},
+		{"padded CGB title", "ZELDA\x00\x00\x00\x00\x00\x00AZ7E", 0x80, useNewLicensee, "ZELDA", "AZ7E"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bytes := make([]byte, romCtrROMEnd+1)
+			copyAt([]byte(tt.title), bytes, titleStart)
+			bytes[oldLicenseeFlag] = tt.licensee
+			if tt.cgb != 0x00 {
+				bytes[cgbFlag] = tt.cgb
+			}
+
+			got, err := ParseHeader(bytes)
+			assert.Err(t, err, false)
+			assert.Equal(t, got.Title, tt.wantTitle)
+			assert.Equal(t, got.ManufacturerCode, tt.wantManufacturer)
+		})
+	}
+}
+
+func TestCartType_String(t *testing.T) {
+	assert.Equal(t, TypeMBC1RAMBattery.String(), "MBC1+RAM+BATTERY")
+	assert.Equal(t, CartType(0x50).String(), "unknown (0x50)")
+}
+
+func TestRomSize(t *testing.T) {
+	assert.Equal(t, romSize(0x00), 2*romBankSize)
+	assert.Equal(t, romSize(0x08), 512*romBankSize)
+	assert.Equal(t, romSize(0x52), 72*romBankSize)
+	assert.Equal(t, romSize(0x09), 0)
+}
+
+func TestRamSize(t *testing.T) {
+	assert.Equal(t, ramSize(0x00), 0)
+	assert.Equal(t, ramSize(valueRAM2KB), 2048)
+	assert.Equal(t, ramSize(valueRAMBank16), 16*ramBankSize)
+}
@@ -1,6 +1,7 @@
 package cart
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/lucactt/gameboy/util/errors"
@@ -26,14 +27,30 @@ const (
 	mbc1EnableRAMValue byte = 0x0A
 )
 
+// Number of ROM banks in an MBC1M multicart, and bank where its second game starts.
+const (
+	mbc1MBanks    int  = 64
+	mbc1MGameBank int  = 0x10
+	mbc1BankBits  uint = 5
+	mbc1MBankBits uint = 4
+	mbc1BankMask  byte = 0x1F
+	mbc1UpperMask byte = 0x03
+)
+
 // MBC1 implements an MBC1 cartridge controller.
 type MBC1 struct {
-	rom          []byte
-	ram          []byte
-	romBank      byte
-	ramBank      byte
+	rom []byte
+	ram []byte
+	// romBank is the 5 bit register written at 0x2000-0x3FFF.
+	romBank byte
+	// upperBank is the 2 bit register written at 0x4000-0x5FFF,
+	// which holds the RAM bank or the upper bits of the ROM bank.
+	upperBank    byte
 	isRAMBanking bool
 	isRAMEnabled bool
+	// isMulticart is true if the controller is wired as an MBC1M,
+	// where only the lower 4 bits of romBank are connected.
+	isMulticart bool
 }
 
 // NewMBC1 creates a new MBC1 controller from the given ROM and RAM banks number.
@@ -51,11 +68,35 @@ func NewMBC1(rom []byte, ram []byte) (*MBC1, error) {
 
 	// The ROM bank is initialized to 0x01 to avoid access to ROM banks 0x00, 0x20, 0x40 and 0x60
 	// from the switchable ROM addresses on startup.
-	// The SetByte method verifies that the lower two bits of the bank are also != 00 to impose this
+	// The SetByte method verifies that the lower five bits of the bank are also != 00 to impose this
 	// after startup.
 	return &MBC1{rom: rom, ram: ram, romBank: 0x01}, nil
 }
 
+// NewMBC1M creates a new MBC1 controller wired as an MBC1M multicart,
+// where the upper bank bits select one of the games in the ROM.
+func NewMBC1M(rom []byte, ram []byte) (*MBC1, error) {
+	ctr, err := NewMBC1(rom, ram)
+	if err != nil {
+		return nil, err
+	}
+
+	ctr.isMulticart = true
+	return ctr, nil
+}
+
+// isMBC1M checks if the ROM is an MBC1M multicart, which is
+// a 1 MB ROM containing a Nintendo logo at the start of
+// the second game.
+func isMBC1M(rom []byte) bool {
+	if len(rom) != mbc1MBanks*romBankSize {
+		return false
+	}
+
+	off := mbc1MGameBank*romBankSize + int(logoStart)
+	return bytes.Equal(rom[off:off+len(nintendoLogo)], nintendoLogo)
+}
+
 // GetByte returns the byte at the given address, which
 // can be read from the ROM or from the RAM, if it exists.
 func (ctr *MBC1) GetByte(addr uint16) (byte, error) {
@@ -66,22 +107,17 @@ func (ctr *MBC1) GetByte(addr uint16) (byte, error) {
 
 	switch {
 	case addr <= mbc1ROMBank0End:
-		return ctr.rom[addr], nil
+		return ctr.rom[ctr.romAddr(ctr.zeroBank(), addr-mbc1ROMBank0Start)], nil
 
 	case addr <= mbc1SwitchROMEnd:
-		// The address to read is equal to the selected ROM bank multiplied by the ROM bank size,
-		// to which the param address is summed. However, the ROM stored in the controller
-		// starts addressing from 0, so the start address of the ROM must be subtracted from the param address.
-		relAddr := int(ctr.romBank)*romBankSize + int(addr-mbc1SwitchROMStart)
-		return ctr.rom[relAddr], nil
+		return ctr.rom[ctr.romAddr(ctr.ROMBank(), addr-mbc1SwitchROMStart)], nil
 
 	case addr >= mbc1SwitchRAMStart && addr <= mbc1SwitchRAMEnd:
 		if !ctr.isRAMEnabled {
 			return 0xFF, nil
 		}
 
-		relAddr := int(ctr.ramBank)*ramBankSize + int(addr-mbc1SwitchRAMStart)
-		return ctr.ram[relAddr], nil
+		return ctr.ram[ctr.ramAddr(addr)], nil
 
 	default:
 		panic(fmt.Errorf("unhandled address %d in mbc1 controller", addr))
@@ -97,43 +133,26 @@ func (ctr *MBC1) SetByte(addr uint16, value byte) error {
 
 	switch {
 	case addr <= mbc1RAMEnableEnd:
-		ctr.isRAMEnabled = (value == mbc1EnableRAMValue)
+		ctr.isRAMEnabled = (value&0x0F == mbc1EnableRAMValue)
 
 	case addr <= mbc1ROMBankEnd:
-		bank := value
-		if bank == 0x00 {
-			bank = 0x01
+		// The zero check is done on all five bits, even if
+		// a multicart only uses the lower four.
+		ctr.romBank = value & mbc1BankMask
+		if ctr.romBank == 0x00 {
+			ctr.romBank = 0x01
 		}
 
-		// Select only the 5th and 6th bits of the current bank
-		bankUpper := (ctr.romBank >> 5) & 0x03
-		// Select only the last five bits
-		bankLower := bank & 0x1F
-		// Set the 5th and 6th bit of the bank, leave the 7th untouched
-		ctr.romBank = bankLower + (bankUpper << 5)
-
 	case addr <= mbc1RAMBankEnd:
-		if ctr.isRAMBanking {
-			ctr.ramBank = value & 0x03
-		} else {
-			bankUpper := value & 0x03
-			bankLower := ctr.romBank & 0x1F
-			ctr.romBank = bankLower + (bankUpper << 5)
-		}
+		ctr.upperBank = value & mbc1UpperMask
 
 	case addr <= mbc1ModeEnd:
-		ctr.isRAMBanking = (value != 0x00)
-
-		if ctr.isRAMBanking {
-			// Clear the upper bits of ROM bank
-			ctr.romBank = ctr.romBank & 0x1F
-		} else {
-			ctr.ramBank = 0
-		}
+		ctr.isRAMBanking = (value&0x01 != 0x00)
 
 	case addr >= mbc1SwitchRAMStart && addr <= mbc1SwitchRAMEnd:
-		relAddr := int(ctr.ramBank)*ramBankSize + int(addr-mbc1SwitchRAMStart)
-		ctr.ram[relAddr] = value
+		if ctr.isRAMEnabled {
+			ctr.ram[ctr.ramAddr(addr)] = value
+		}
 
 	default:
 		panic(fmt.Errorf("unhandled address %d in mbc1 controller", addr))
@@ -142,6 +161,62 @@ func (ctr *MBC1) SetByte(addr uint16, value byte) error {
 	return nil
 }
 
+// RAMEnabled returns true if the RAM is enabled, so that it can be read and written.
+func (ctr *MBC1) RAMEnabled() bool {
+	return ctr.isRAMEnabled
+}
+
+// ROMBank returns the ROM bank mapped to the switchable ROM addresses.
+func (ctr *MBC1) ROMBank() int {
+	lower := ctr.romBank
+	if ctr.isMulticart {
+		lower &= mbc1BankMask >> 1
+	}
+
+	return ctr.wrapROMBank(int(ctr.upperBank)<<ctr.bankBits() | int(lower))
+}
+
+// zeroBank returns the ROM bank mapped to 0x0000-0x3FFF, which
+// is switched by the upper bank bits only in RAM banking mode.
+func (ctr *MBC1) zeroBank() int {
+	if !ctr.isRAMBanking {
+		return 0
+	}
+	return ctr.wrapROMBank(int(ctr.upperBank) << ctr.bankBits())
+}
+
+// bankBits returns the number of ROM bank bits
+// selected by the 0x2000-0x3FFF register.
+func (ctr *MBC1) bankBits() uint {
+	if ctr.isMulticart {
+		return mbc1MBankBits
+	}
+	return mbc1BankBits
+}
+
+// wrapROMBank ignores the bank bits not wired to the ROM, so that
+// ROMs smaller than the selected bank wrap around.
+func (ctr *MBC1) wrapROMBank(bank int) int {
+	return bank % (len(ctr.rom) / romBankSize)
+}
+
+// romAddr returns the index in the ROM of the given offset in a bank.
+func (ctr *MBC1) romAddr(bank int, off uint16) int {
+	return bank*romBankSize + int(off)
+}
+
+// ramAddr returns the index in the RAM of the given address, which
+// depends on the selected RAM bank only in RAM banking mode.
+// If the selected bank is larger than the RAM, the address wraps around.
+func (ctr *MBC1) ramAddr(addr uint16) int {
+	bank := 0
+	if ctr.isRAMBanking {
+		bank = int(ctr.upperBank)
+	}
+
+	return (bank*ramBankSize + int(addr-mbc1SwitchRAMStart)) % len(ctr.ram)
+}
+
 // Accepts returns true if the address is included in the ROM
 // or in the RAM, false otherwise.
 func (ctr *MBC1) Accepts(addr uint16) bool {
@@ -18,6 +18,26 @@ func TestNewMBC1(t *testing.T) {
 	})
 }
 
+func TestIsMBC1M(t *testing.T) {
+	t.Run("logo at game boundary", func(t *testing.T) {
+		bytes := make([]byte, mbc1MBanks*romBankSize)
+		copy(bytes[mbc1MGameBank*romBankSize+int(logoStart):], nintendoLogo)
+
+		assert.Equal(t, isMBC1M(bytes), true)
+	})
+
+	t.Run("no logo", func(t *testing.T) {
+		assert.Equal(t, isMBC1M(make([]byte, mbc1MBanks*romBankSize)), false)
+	})
+
+	t.Run("not 1 MB", func(t *testing.T) {
+		bytes := make([]byte, 2*mbc1MBanks*romBankSize)
+		copy(bytes[mbc1MGameBank*romBankSize+int(logoStart):], nintendoLogo)
+
+		assert.Equal(t, isMBC1M(bytes), false)
+	})
+}
+
 func TestMBC1_GetByte(t *testing.T) {
 	t.Run("ROM bank", func(t *testing.T) {
 		bytes := make([]byte, 2*romBankSize)
@@ -161,6 +181,48 @@ func TestMBC1_SetByte(t *testing.T) {
 		assert.Equal(t, got, byte(0x11))
 	})
 
+	t.Run("ROM bank larger than ROM wraps", func(t *testing.T) {
+		bytes := make([]byte, 4*romBankSize)
+		bytes[2*(romBankSize)-1] = 0x11
+
+		ctr, _ := NewMBC1(bytes, make([]byte, 0))
+		ctr.SetByte(mbc1ROMBankStart, 0x05)
+
+		got, err := ctr.GetByte(mbc1SwitchROMEnd)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+		assert.Equal(t, ctr.ROMBank(), 1)
+	})
+
+	t.Run("RAM bank larger than RAM wraps", func(t *testing.T) {
+		ctr, _ := NewMBC1(make([]byte, 2*romBankSize), make([]byte, ramBankSize))
+		ctr.SetByte(mbc1ModeStart, 0x01)
+		ctr.SetByte(mbc1RAMBankStart, 0x03)
+		ctr.SetByte(mbc1RAMEnableStart, mbc1EnableRAMValue)
+
+		err := ctr.SetByte(mbc1SwitchRAMStart, 0x11)
+		assert.Err(t, err, false)
+
+		ctr.SetByte(mbc1ModeStart, 0x00)
+
+		got, err := ctr.GetByte(mbc1SwitchRAMStart)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+	})
+
+	t.Run("RAM banking switches ROM bank 0", func(t *testing.T) {
+		bytes := make([]byte, 64*romBankSize)
+		bytes[0x20*romBankSize] = 0x11
+
+		ctr, _ := NewMBC1(bytes, make([]byte, 0))
+		ctr.SetByte(mbc1ModeStart, 0x01)
+		ctr.SetByte(mbc1RAMBankStart, 0x01)
+
+		got, err := ctr.GetByte(mbc1ROMBank0Start)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+	})
+
 	t.Run("Lower bit of ROM bank converted to 1 if 0", func(t *testing.T) {
 		bytes := make([]byte, 2*romBankSize)
 		bytes[2*(romBankSize)-1] = 0x11
@@ -174,6 +236,34 @@ func TestMBC1_SetByte(t *testing.T) {
 	})
 }
 
+func TestMBC1M(t *testing.T) {
+	t.Run("Upper bits select game", func(t *testing.T) {
+		bytes := make([]byte, mbc1MBanks*romBankSize)
+		bytes[0x10*romBankSize] = 0x11
+		bytes[0x12*romBankSize] = 0x22
+
+		ctr, _ := NewMBC1M(bytes, make([]byte, 0))
+		ctr.SetByte(mbc1ModeStart, 0x01)
+		ctr.SetByte(mbc1RAMBankStart, 0x01)
+		ctr.SetByte(mbc1ROMBankStart, 0x02)
+
+		got, err := ctr.GetByte(mbc1ROMBank0Start)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+
+		got, err = ctr.GetByte(mbc1SwitchROMStart)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x22))
+	})
+
+	t.Run("Fifth bank bit is not wired", func(t *testing.T) {
+		ctr, _ := NewMBC1M(make([]byte, mbc1MBanks*romBankSize), make([]byte, 0))
+		ctr.SetByte(mbc1ROMBankStart, 0x10)
+
+		assert.Equal(t, ctr.ROMBank(), 0)
+	})
+}
+
 func TestMBC1_Accepts(t *testing.T) {
 	t.Run("RAM address", func(t *testing.T) {
 		ctr, _ := NewMBC1(make([]byte, 2*romBankSize), make([]byte, ramBankSize))
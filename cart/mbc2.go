@@ -0,0 +1,143 @@
+package cart
+
+import (
+	"fmt"
+
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// Memory addresses
+const (
+	mbc2ROMBank0Start  uint16 = 0x0000
+	mbc2ROMBank0End    uint16 = 0x3FFF
+	mbc2SwitchROMStart uint16 = 0x4000
+	mbc2SwitchROMEnd   uint16 = 0x7FFF
+	mbc2RAMStart       uint16 = 0xA000
+	mbc2RAMEnd         uint16 = 0xBFFF
+	mbc2RegistersEnd   uint16 = 0x3FFF
+
+	// mbc2ROMBankBit is the address bit that selects whether
+	// a write to the registers sets the ROM bank or enables the RAM.
+	mbc2ROMBankBit uint16 = 0x0100
+
+	mbc2EnableRAMValue byte = 0x0A
+	mbc2ROMBankMask    byte = 0x0F
+)
+
+// mbc2RAMSize is the number of 4 bit cells in the MBC2 built-in RAM.
+const mbc2RAMSize int = 512
+
+// MBC2 implements an MBC2 cartridge controller.
+//
+// The controller contains 512 half-byte RAM cells, which are
+// echoed across all the RAM addresses.
+type MBC2 struct {
+	rom          []byte
+	ram          []byte
+	romBank      byte
+	isRAMEnabled bool
+}
+
+// NewMBC2 creates a new MBC2 controller from the given ROM.
+// The RAM is built into the controller, so the
+// RAM size in the header is ignored.
+//
+// The ROM must be large enough to contain at least two banks.
+func NewMBC2(rom []byte) (*MBC2, error) {
+	if rom == nil {
+		panic(fmt.Errorf("the rom is nil"))
+	}
+
+	if len(rom) < 2*romBankSize {
+		return nil, errors.E("rom size insufficient: must contain at least two banks", errors.Cart)
+	}
+
+	return &MBC2{rom: rom, ram: make([]byte, mbc2RAMSize), romBank: 0x01}, nil
+}
+
+// GetByte returns the byte at the given address, which
+// can be read from the ROM or from the RAM.
+// Only the lower 4 bits of the RAM are stored, the upper
+// bits always read as 1.
+func (ctr *MBC2) GetByte(addr uint16) (byte, error) {
+	if !ctr.Accepts(addr) {
+		return 0, errors.E(fmt.Sprintf("mbc2 controller does not accept addr %d", addr), errors.Cart)
+	}
+
+	switch {
+	case addr <= mbc2ROMBank0End:
+		return ctr.rom[addr], nil
+
+	case addr <= mbc2SwitchROMEnd:
+		relAddr := ctr.ROMBank()*romBankSize + int(addr-mbc2SwitchROMStart)
+		return ctr.rom[relAddr], nil
+
+	case addr >= mbc2RAMStart && addr <= mbc2RAMEnd:
+		if !ctr.isRAMEnabled {
+			return 0xFF, nil
+		}
+
+		return ctr.ram[ctr.ramAddr(addr)] | 0xF0, nil
+
+	default:
+		panic(fmt.Errorf("unhandled address %d in mbc2 controller", addr))
+	}
+}
+
+// SetByte writes the registers if the addr points to the
+// ROM bank 0, or sets the lower 4 bits of the RAM cell if it points to RAM.
+// Writes to the switchable ROM bank are ignored.
+func (ctr *MBC2) SetByte(addr uint16, value byte) error {
+	if !ctr.Accepts(addr) {
+		return errors.E(fmt.Sprintf("mbc2 controller does not accept addr %d", addr), errors.Cart)
+	}
+
+	switch {
+	case addr <= mbc2RegistersEnd:
+		if addr&mbc2ROMBankBit == 0 {
+			ctr.isRAMEnabled = (value&0x0F == mbc2EnableRAMValue)
+			break
+		}
+
+		ctr.romBank = value & mbc2ROMBankMask
+		if ctr.romBank == 0x00 {
+			ctr.romBank = 0x01
+		}
+
+	case addr <= mbc2SwitchROMEnd:
+		break
+
+	case addr >= mbc2RAMStart && addr <= mbc2RAMEnd:
+		if ctr.isRAMEnabled {
+			ctr.ram[ctr.ramAddr(addr)] = value & 0x0F
+		}
+
+	default:
+		panic(fmt.Errorf("unhandled address %d in mbc2 controller", addr))
+	}
+
+	return nil
+}
+
+// RAMEnabled returns true if the RAM is enabled, so that it can be read and written.
+func (ctr *MBC2) RAMEnabled() bool {
+	return ctr.isRAMEnabled
+}
+
+// ROMBank returns the ROM bank mapped to the switchable ROM addresses.
+// If the ROM is smaller than the selected bank, the bank wraps around.
+func (ctr *MBC2) ROMBank() int {
+	return int(ctr.romBank) % (len(ctr.rom) / romBankSize)
+}
+
+// ramAddr returns the index in the RAM of the given address.
+// The RAM is echoed every 512 bytes.
+func (ctr *MBC2) ramAddr(addr uint16) int {
+	return int(addr-mbc2RAMStart) % mbc2RAMSize
+}
+
+// Accepts returns true if the address is included in the ROM
+// or in the RAM, false otherwise.
+func (ctr *MBC2) Accepts(addr uint16) bool {
+	return (addr <= mbc2SwitchROMEnd) || (addr >= mbc2RAMStart && addr <= mbc2RAMEnd)
+}
@@ -0,0 +1,160 @@
+package cart
+
+import (
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+func TestNewMBC2(t *testing.T) {
+	t.Run("ROM is too small", func(t *testing.T) {
+		_, err := NewMBC2(make([]byte, 0))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("ROM is big enough", func(t *testing.T) {
+		_, err := NewMBC2(make([]byte, 2*romBankSize))
+		assert.Err(t, err, false)
+	})
+}
+
+func TestMBC2_GetByte(t *testing.T) {
+	t.Run("ROM bank", func(t *testing.T) {
+		bytes := make([]byte, 2*romBankSize)
+		bytes[mbc2ROMBank0End] = 0x11
+
+		ctr, _ := NewMBC2(bytes)
+
+		got, err := ctr.GetByte(mbc2ROMBank0End)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+	})
+
+	t.Run("RAM, RAM enabled", func(t *testing.T) {
+		ctr, _ := NewMBC2(make([]byte, 2*romBankSize))
+
+		// Make sure RAM is enabled
+		ctr.SetByte(mbc2ROMBank0Start, mbc2EnableRAMValue)
+		ctr.SetByte(mbc2RAMStart, 0x01)
+
+		got, err := ctr.GetByte(mbc2RAMStart)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0xF1))
+	})
+
+	t.Run("RAM, RAM disabled", func(t *testing.T) {
+		ctr, _ := NewMBC2(make([]byte, 2*romBankSize))
+
+		// Make sure RAM is disabled
+		ctr.SetByte(mbc2ROMBank0Start, 0x00)
+
+		got, err := ctr.GetByte(mbc2RAMStart)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0xFF))
+	})
+
+	t.Run("RAM is echoed", func(t *testing.T) {
+		ctr, _ := NewMBC2(make([]byte, 2*romBankSize))
+
+		ctr.SetByte(mbc2ROMBank0Start, mbc2EnableRAMValue)
+		ctr.SetByte(mbc2RAMStart+0x01, 0x05)
+
+		got, err := ctr.GetByte(mbc2RAMEnd - 0x01FE)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0xF5))
+	})
+}
+
+func TestMBC2_SetByte(t *testing.T) {
+	t.Run("Enable RAM", func(t *testing.T) {
+		ctr, _ := NewMBC2(make([]byte, 2*romBankSize))
+
+		ctr.SetByte(mbc2ROMBank0Start, mbc2EnableRAMValue)
+		ctr.SetByte(mbc2RAMStart, 0x0A)
+
+		got, err := ctr.GetByte(mbc2RAMStart)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0xFA))
+	})
+
+	t.Run("Write with RAM disabled is ignored", func(t *testing.T) {
+		ctr, _ := NewMBC2(make([]byte, 2*romBankSize))
+
+		ctr.SetByte(mbc2RAMStart, 0x0A)
+		ctr.SetByte(mbc2ROMBank0Start, mbc2EnableRAMValue)
+
+		got, err := ctr.GetByte(mbc2RAMStart)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0xF0))
+	})
+
+	t.Run("Switch ROM bank", func(t *testing.T) {
+		bytes := make([]byte, 16*romBankSize)
+		bytes[16*(romBankSize)-1] = 0x11
+
+		ctr, _ := NewMBC2(bytes)
+		ctr.SetByte(mbc2ROMBankBit, 0x0F)
+
+		got, err := ctr.GetByte(mbc2SwitchROMEnd)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+	})
+
+	t.Run("ROM bank needs address bit 8", func(t *testing.T) {
+		ctr, _ := NewMBC2(make([]byte, 16*romBankSize))
+		ctr.SetByte(mbc2ROMBank0Start, 0x0F)
+
+		assert.Equal(t, ctr.ROMBank(), 1)
+	})
+
+	t.Run("ROM bank converted to 1 if 0", func(t *testing.T) {
+		bytes := make([]byte, 2*romBankSize)
+		bytes[2*(romBankSize)-1] = 0x11
+
+		ctr, _ := NewMBC2(bytes)
+		ctr.SetByte(mbc2ROMBankBit, 0x00)
+
+		got, err := ctr.GetByte(mbc2SwitchROMEnd)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+	})
+
+	t.Run("ROM bank larger than ROM wraps", func(t *testing.T) {
+		ctr, _ := NewMBC2(make([]byte, 4*romBankSize))
+		ctr.SetByte(mbc2ROMBankBit, 0x05)
+
+		assert.Equal(t, ctr.ROMBank(), 1)
+	})
+}
+
+func TestMBC2_Accepts(t *testing.T) {
+	t.Run("RAM address", func(t *testing.T) {
+		ctr, _ := NewMBC2(make([]byte, 2*romBankSize))
+
+		got := ctr.Accepts(mbc2RAMStart)
+		assert.Equal(t, got, true)
+
+		got = ctr.Accepts(mbc2RAMEnd)
+		assert.Equal(t, got, true)
+	})
+
+	t.Run("ROM address", func(t *testing.T) {
+		ctr, _ := NewMBC2(make([]byte, 2*romBankSize))
+
+		got := ctr.Accepts(mbc2ROMBank0Start)
+		assert.Equal(t, got, true)
+
+		got = ctr.Accepts(mbc2SwitchROMEnd)
+		assert.Equal(t, got, true)
+	})
+
+	t.Run("Outside mem", func(t *testing.T) {
+		ctr, _ := NewMBC2(make([]byte, 2*romBankSize))
+
+		got := ctr.Accepts(0xFFFF)
+		assert.Equal(t, got, false)
+
+		got = ctr.Accepts(mbc2RAMEnd + 1)
+		assert.Equal(t, got, false)
+	})
+}
@@ -0,0 +1,176 @@
+package cart
+
+import (
+	"fmt"
+
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// Memory addresses
+const (
+	mbc3ROMBank0Start  uint16 = 0x0000
+	mbc3ROMBank0End    uint16 = 0x3FFF
+	mbc3SwitchROMStart uint16 = 0x4000
+	mbc3SwitchROMEnd   uint16 = 0x7FFF
+	mbc3SwitchRAMStart uint16 = 0xA000
+	mbc3SwitchRAMEnd   uint16 = 0xBFFF
+	mbc3RAMEnableStart uint16 = 0x0000
+	mbc3RAMEnableEnd   uint16 = 0x1FFF
+	mbc3ROMBankStart   uint16 = 0x2000
+	mbc3ROMBankEnd     uint16 = 0x3FFF
+	mbc3RAMBankStart   uint16 = 0x4000
+	mbc3RAMBankEnd     uint16 = 0x5FFF
+	mbc3LatchStart     uint16 = 0x6000
+	mbc3LatchEnd       uint16 = 0x7FFF
+
+	mbc3EnableRAMValue byte = 0x0A
+	mbc3ROMBankMask    byte = 0x7F
+)
+
+// MBC3 implements an MBC3 cartridge controller,
+// optionally with a real time clock.
+type MBC3 struct {
+	rom []byte
+	ram []byte
+	rtc *RTC
+	// romBank is the 7 bit ROM bank register.
+	romBank byte
+	// ramBank selects a RAM bank or, if between 0x08 and 0x0C,
+	// an RTC register.
+	ramBank      byte
+	isRAMEnabled bool
+	// latch is the last value written to the latch register.
+	latch byte
+}
+
+// NewMBC3 creates a new MBC3 controller from the given ROM, RAM and RTC.
+//
+// The ROM must be large enough to contain at least two banks.
+// The RAM can have length == 0, but cannot be nil.
+// The RTC can be nil if the cartridge has no timer.
+func NewMBC3(rom []byte, ram []byte, rtc *RTC) (*MBC3, error) {
+	if rom == nil || ram == nil {
+		panic(fmt.Errorf("the rom or ram are nil"))
+	}
+
+	if len(rom) < 2*romBankSize {
+		return nil, errors.E("rom size insufficient: must contain at least two banks", errors.Cart)
+	}
+
+	return &MBC3{rom: rom, ram: ram, rtc: rtc, romBank: 0x01, latch: 0xFF}, nil
+}
+
+// GetByte returns the byte at the given address, which
+// can be read from the ROM, from the RAM or from the selected RTC register.
+func (ctr *MBC3) GetByte(addr uint16) (byte, error) {
+	if !ctr.Accepts(addr) {
+		return 0, errors.E(fmt.Sprintf("mbc3 controller does not accept addr %d", addr), errors.Cart)
+	}
+
+	switch {
+	case addr <= mbc3ROMBank0End:
+		return ctr.rom[addr], nil
+
+	case addr <= mbc3SwitchROMEnd:
+		relAddr := ctr.ROMBank()*romBankSize + int(addr-mbc3SwitchROMStart)
+		return ctr.rom[relAddr], nil
+
+	case addr >= mbc3SwitchRAMStart && addr <= mbc3SwitchRAMEnd:
+		if !ctr.isRAMEnabled {
+			return 0xFF, nil
+		}
+
+		if ctr.ramBank >= rtcSeconds {
+			if ctr.rtc == nil {
+				return 0xFF, nil
+			}
+			return ctr.rtc.Get(ctr.ramBank), nil
+		}
+
+		if len(ctr.ram) == 0 {
+			return 0xFF, nil
+		}
+		return ctr.ram[ctr.ramAddr(addr)], nil
+
+	default:
+		panic(fmt.Errorf("unhandled address %d in mbc3 controller", addr))
+	}
+}
+
+// SetByte writes the registers if the addr points to the ROM,
+// or sets the byte in the RAM or in the selected RTC register.
+func (ctr *MBC3) SetByte(addr uint16, value byte) error {
+	if !ctr.Accepts(addr) {
+		return errors.E(fmt.Sprintf("mbc3 controller does not accept addr %d", addr), errors.Cart)
+	}
+
+	switch {
+	case addr <= mbc3RAMEnableEnd:
+		ctr.isRAMEnabled = (value&0x0F == mbc3EnableRAMValue)
+
+	case addr <= mbc3ROMBankEnd:
+		ctr.romBank = value & mbc3ROMBankMask
+		if ctr.romBank == 0x00 {
+			ctr.romBank = 0x01
+		}
+
+	case addr <= mbc3RAMBankEnd:
+		ctr.ramBank = value
+
+	case addr <= mbc3LatchEnd:
+		// The clock is latched by writing 0x00 and then 0x01.
+		if ctr.latch == 0x00 && value == 0x01 && ctr.rtc != nil {
+			ctr.rtc.Latch()
+		}
+		ctr.latch = value
+
+	case addr >= mbc3SwitchRAMStart && addr <= mbc3SwitchRAMEnd:
+		if !ctr.isRAMEnabled {
+			break
+		}
+
+		if ctr.ramBank >= rtcSeconds {
+			if ctr.rtc != nil {
+				ctr.rtc.Set(ctr.ramBank, value)
+			}
+			break
+		}
+
+		if len(ctr.ram) > 0 {
+			ctr.ram[ctr.ramAddr(addr)] = value
+		}
+
+	default:
+		panic(fmt.Errorf("unhandled address %d in mbc3 controller", addr))
+	}
+
+	return nil
+}
+
+// RAMEnabled returns true if the RAM and the clock registers are enabled,
+// so that they can be read and written.
+func (ctr *MBC3) RAMEnabled() bool {
+	return ctr.isRAMEnabled
+}
+
+// ROMBank returns the ROM bank mapped to the switchable ROM addresses.
+// If the ROM is smaller than the selected bank, the bank wraps around.
+func (ctr *MBC3) ROMBank() int {
+	return int(ctr.romBank) % (len(ctr.rom) / romBankSize)
+}
+
+// ramAddr returns the index in the RAM of the given address.
+// If the selected bank is larger than the RAM, the address wraps around.
+func (ctr *MBC3) ramAddr(addr uint16) int {
+	return (int(ctr.ramBank)*ramBankSize + int(addr-mbc3SwitchRAMStart)) % len(ctr.ram)
+}
+
+// Accepts returns true if the address is included in the ROM
+// or in the RAM, false otherwise.
+func (ctr *MBC3) Accepts(addr uint16) bool {
+	if addr >= mbc3SwitchRAMStart && addr <= mbc3SwitchRAMEnd && len(ctr.ram) == 0 && ctr.rtc == nil {
+		return false
+	}
+
+	return (addr <= mbc3SwitchROMEnd) || (addr >= mbc3SwitchRAMStart && addr <= mbc3SwitchRAMEnd)
+}
@@ -0,0 +1,195 @@
+package cart
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+func TestNewMBC3(t *testing.T) {
+	t.Run("ROM is too small", func(t *testing.T) {
+		_, err := NewMBC3(make([]byte, 0), make([]byte, 0), nil)
+		assert.Err(t, err, true)
+	})
+
+	t.Run("ROM is big enough", func(t *testing.T) {
+		_, err := NewMBC3(make([]byte, 2*romBankSize), make([]byte, 0), nil)
+		assert.Err(t, err, false)
+	})
+}
+
+func TestMBC3_GetByte(t *testing.T) {
+	t.Run("ROM bank", func(t *testing.T) {
+		bytes := make([]byte, 2*romBankSize)
+		bytes[mbc3ROMBank0End] = 0x11
+
+		ctr, _ := NewMBC3(bytes, make([]byte, 0), nil)
+
+		got, err := ctr.GetByte(mbc3ROMBank0End)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+	})
+
+	t.Run("RAM bank, RAM disabled", func(t *testing.T) {
+		ctr, _ := NewMBC3(make([]byte, 2*romBankSize), make([]byte, ramBankSize), nil)
+
+		got, err := ctr.GetByte(mbc3SwitchRAMEnd)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0xFF))
+	})
+
+	t.Run("RAM bank, but no RAM", func(t *testing.T) {
+		ctr, _ := NewMBC3(make([]byte, 2*romBankSize), make([]byte, 0), nil)
+
+		_, err := ctr.GetByte(mbc3SwitchRAMEnd)
+		assert.Err(t, err, true)
+	})
+
+	t.Run("RTC register, but no RTC", func(t *testing.T) {
+		ctr, _ := NewMBC3(make([]byte, 2*romBankSize), make([]byte, ramBankSize), nil)
+		ctr.SetByte(mbc3RAMEnableStart, mbc3EnableRAMValue)
+		ctr.SetByte(mbc3RAMBankStart, rtcSeconds)
+
+		got, err := ctr.GetByte(mbc3SwitchRAMStart)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0xFF))
+	})
+}
+
+func TestMBC3_SetByte(t *testing.T) {
+	t.Run("Enable RAM", func(t *testing.T) {
+		ctr, _ := NewMBC3(make([]byte, 2*romBankSize), make([]byte, ramBankSize), nil)
+
+		ctr.SetByte(mbc3RAMEnableStart, mbc3EnableRAMValue)
+		ctr.SetByte(mbc3SwitchRAMStart, 0x11)
+
+		got, err := ctr.GetByte(mbc3SwitchRAMStart)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+	})
+
+	t.Run("Switch RAM bank", func(t *testing.T) {
+		bytes := make([]byte, 4*ramBankSize)
+		bytes[4*ramBankSize-1] = 0x11
+
+		ctr, _ := NewMBC3(make([]byte, 2*romBankSize), bytes, nil)
+		ctr.SetByte(mbc3RAMEnableStart, mbc3EnableRAMValue)
+		ctr.SetByte(mbc3RAMBankStart, 0x03)
+
+		got, err := ctr.GetByte(mbc3SwitchRAMEnd)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+	})
+
+	t.Run("Switch ROM bank", func(t *testing.T) {
+		bytes := make([]byte, 128*romBankSize)
+		bytes[128*romBankSize-1] = 0x11
+
+		ctr, _ := NewMBC3(bytes, make([]byte, 0), nil)
+		ctr.SetByte(mbc3ROMBankStart, 0x7F)
+
+		got, err := ctr.GetByte(mbc3SwitchROMEnd)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+	})
+
+	t.Run("ROM bank converted to 1 if 0", func(t *testing.T) {
+		ctr, _ := NewMBC3(make([]byte, 4*romBankSize), make([]byte, 0), nil)
+		ctr.SetByte(mbc3ROMBankStart, 0x00)
+
+		assert.Equal(t, ctr.ROMBank(), 1)
+	})
+
+	t.Run("ROM bank larger than ROM wraps", func(t *testing.T) {
+		ctr, _ := NewMBC3(make([]byte, 4*romBankSize), make([]byte, 0), nil)
+		ctr.SetByte(mbc3ROMBankStart, 0x06)
+
+		assert.Equal(t, ctr.ROMBank(), 2)
+	})
+}
+
+func TestMBC3_RTC(t *testing.T) {
+	setup := func() (*MBC3, *fakeClock) {
+		clock := newFakeClock()
+		ctr, _ := NewMBC3(make([]byte, 2*romBankSize), make([]byte, 0), NewRTC(clock.now))
+		ctr.SetByte(mbc3RAMEnableStart, mbc3EnableRAMValue)
+		return ctr, clock
+	}
+
+	latch := func(ctr *MBC3) {
+		ctr.SetByte(mbc3LatchStart, 0x00)
+		ctr.SetByte(mbc3LatchStart, 0x01)
+	}
+
+	t.Run("latch sequence", func(t *testing.T) {
+		ctr, clock := setup()
+		ctr.SetByte(mbc3RAMBankStart, rtcMinutes)
+
+		clock.advance(2 * time.Minute)
+		latch(ctr)
+
+		got, err := ctr.GetByte(mbc3SwitchRAMStart)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(2))
+	})
+
+	t.Run("registers frozen until latched again", func(t *testing.T) {
+		ctr, clock := setup()
+		ctr.SetByte(mbc3RAMBankStart, rtcSeconds)
+		latch(ctr)
+
+		clock.advance(5 * time.Second)
+		ctr.SetByte(mbc3LatchStart, 0x01)
+
+		got, _ := ctr.GetByte(mbc3SwitchRAMStart)
+		assert.Equal(t, got, byte(0))
+
+		latch(ctr)
+
+		got, _ = ctr.GetByte(mbc3SwitchRAMStart)
+		assert.Equal(t, got, byte(5))
+	})
+
+	t.Run("write register", func(t *testing.T) {
+		ctr, clock := setup()
+		ctr.SetByte(mbc3RAMBankStart, rtcHours)
+		ctr.SetByte(mbc3SwitchRAMStart, 23)
+
+		clock.advance(time.Hour)
+		latch(ctr)
+
+		got, _ := ctr.GetByte(mbc3SwitchRAMStart)
+		assert.Equal(t, got, byte(0))
+
+		ctr.SetByte(mbc3RAMBankStart, rtcDaysLow)
+		got, _ = ctr.GetByte(mbc3SwitchRAMStart)
+		assert.Equal(t, got, byte(1))
+	})
+}
+
+func TestMBC3_Accepts(t *testing.T) {
+	t.Run("RAM address, but no RAM", func(t *testing.T) {
+		ctr, _ := NewMBC3(make([]byte, 2*romBankSize), make([]byte, 0), nil)
+
+		got := ctr.Accepts(mbc3SwitchRAMStart)
+		assert.Equal(t, got, false)
+	})
+
+	t.Run("RAM address, RTC only", func(t *testing.T) {
+		ctr, _ := NewMBC3(make([]byte, 2*romBankSize), make([]byte, 0), NewRTC(time.Now))
+
+		got := ctr.Accepts(mbc3SwitchRAMEnd)
+		assert.Equal(t, got, true)
+	})
+
+	t.Run("Outside mem", func(t *testing.T) {
+		ctr, _ := NewMBC3(make([]byte, 2*romBankSize), make([]byte, ramBankSize), nil)
+
+		got := ctr.Accepts(0xFFFF)
+		assert.Equal(t, got, false)
+
+		got = ctr.Accepts(mbc3SwitchRAMEnd + 1)
+		assert.Equal(t, got, false)
+	})
+}
@@ -0,0 +1,181 @@
+package cart
+
+import (
+	"fmt"
+
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// Memory addresses
+const (
+	mbc5ROMBank0Start    uint16 = 0x0000
+	mbc5ROMBank0End      uint16 = 0x3FFF
+	mbc5SwitchROMStart   uint16 = 0x4000
+	mbc5SwitchROMEnd     uint16 = 0x7FFF
+	mbc5SwitchRAMStart   uint16 = 0xA000
+	mbc5SwitchRAMEnd     uint16 = 0xBFFF
+	mbc5RAMEnableStart   uint16 = 0x0000
+	mbc5RAMEnableEnd     uint16 = 0x1FFF
+	mbc5ROMBankLowStart  uint16 = 0x2000
+	mbc5ROMBankLowEnd    uint16 = 0x2FFF
+	mbc5ROMBankHighStart uint16 = 0x3000
+	mbc5ROMBankHighEnd   uint16 = 0x3FFF
+	mbc5RAMBankStart     uint16 = 0x4000
+	mbc5RAMBankEnd       uint16 = 0x5FFF
+
+	mbc5EnableRAMValue byte = 0x0A
+	mbc5RAMBankMask    byte = 0x0F
+
+	// mbc5RumbleBit is the bit of the RAM bank register
+	// connected to the rumble motor, if the cartridge has one.
+	mbc5RumbleBit byte = 0x08
+)
+
+// RumbleFunc is called when the rumble motor of
+// a cartridge is turned on or off.
+type RumbleFunc func(on bool)
+
+// MBC5 implements an MBC5 cartridge controller.
+type MBC5 struct {
+	rom []byte
+	ram []byte
+	// romBank is the 9 bit ROM bank, made of the low
+	// and high ROM bank registers.
+	romBank      uint16
+	ramBank      byte
+	isRAMEnabled bool
+	hasRumble    bool
+	isRumbling   bool
+	rumble       RumbleFunc
+}
+
+// NewMBC5 creates a new MBC5 controller from the given ROM and RAM.
+// If hasRumble is true, bit 3 of the RAM bank register drives the
+// rumble motor instead of selecting a RAM bank.
+//
+// The ROM must be large enough to contain at least two banks.
+// The RAM can have length == 0, but cannot be nil.
+func NewMBC5(rom []byte, ram []byte, hasRumble bool) (*MBC5, error) {
+	if rom == nil || ram == nil {
+		panic(fmt.Errorf("the rom or ram are nil"))
+	}
+
+	if len(rom) < 2*romBankSize {
+		return nil, errors.E("rom size insufficient: must contain at least two banks", errors.Cart)
+	}
+
+	return &MBC5{rom: rom, ram: ram, romBank: 0x01, hasRumble: hasRumble}, nil
+}
+
+// SetRumble sets the function called when the rumble motor
+// is turned on or off. It is never called if the cartridge has no rumble.
+func (ctr *MBC5) SetRumble(f RumbleFunc) {
+	ctr.rumble = f
+}
+
+// GetByte returns the byte at the given address, which
+// can be read from the ROM or from the RAM, if it exists.
+func (ctr *MBC5) GetByte(addr uint16) (byte, error) {
+	if !ctr.Accepts(addr) {
+		return 0, errors.E(fmt.Sprintf("mbc5 controller does not accept addr %d", addr), errors.Cart)
+	}
+
+	switch {
+	case addr <= mbc5ROMBank0End:
+		return ctr.rom[addr], nil
+
+	case addr <= mbc5SwitchROMEnd:
+		relAddr := ctr.ROMBank()*romBankSize + int(addr-mbc5SwitchROMStart)
+		return ctr.rom[relAddr], nil
+
+	case addr >= mbc5SwitchRAMStart && addr <= mbc5SwitchRAMEnd:
+		if !ctr.isRAMEnabled {
+			return 0xFF, nil
+		}
+
+		return ctr.ram[ctr.ramAddr(addr)], nil
+
+	default:
+		panic(fmt.Errorf("unhandled address %d in mbc5 controller", addr))
+	}
+}
+
+// SetByte writes the registers if the addr points to the ROM,
+// or sets the byte to the given value if it points to RAM.
+func (ctr *MBC5) SetByte(addr uint16, value byte) error {
+	if !ctr.Accepts(addr) {
+		return errors.E(fmt.Sprintf("mbc5 controller does not accept addr %d", addr), errors.Cart)
+	}
+
+	switch {
+	case addr <= mbc5RAMEnableEnd:
+		ctr.isRAMEnabled = (value&0x0F == mbc5EnableRAMValue)
+
+	case addr <= mbc5ROMBankLowEnd:
+		// Unlike MBC1 and MBC3, bank 0 can be mapped to the switchable ROM.
+		ctr.romBank = ctr.romBank&0x100 | uint16(value)
+
+	case addr <= mbc5ROMBankHighEnd:
+		ctr.romBank = ctr.romBank&0xFF | uint16(value&0x01)<<8
+
+	case addr <= mbc5RAMBankEnd:
+		ctr.ramBank = value & mbc5RAMBankMask
+		if ctr.hasRumble {
+			ctr.ramBank &^= mbc5RumbleBit
+			ctr.setRumbling(value&mbc5RumbleBit != 0)
+		}
+
+	case addr <= mbc5SwitchROMEnd:
+		break
+
+	case addr >= mbc5SwitchRAMStart && addr <= mbc5SwitchRAMEnd:
+		if ctr.isRAMEnabled {
+			ctr.ram[ctr.ramAddr(addr)] = value
+		}
+
+	default:
+		panic(fmt.Errorf("unhandled address %d in mbc5 controller", addr))
+	}
+
+	return nil
+}
+
+// setRumbling turns the rumble motor on or off,
+// calling the rumble function only if the state has changed.
+func (ctr *MBC5) setRumbling(on bool) {
+	if on == ctr.isRumbling {
+		return
+	}
+
+	ctr.isRumbling = on
+	if ctr.rumble != nil {
+		ctr.rumble(on)
+	}
+}
+
+// RAMEnabled returns true if the RAM is enabled, so that it can be read and written.
+func (ctr *MBC5) RAMEnabled() bool {
+	return ctr.isRAMEnabled
+}
+
+// ROMBank returns the ROM bank mapped to the switchable ROM addresses.
+// If the ROM is smaller than the selected bank, the bank wraps around.
+func (ctr *MBC5) ROMBank() int {
+	return int(ctr.romBank) % (len(ctr.rom) / romBankSize)
+}
+
+// ramAddr returns the index in the RAM of the given address.
+// If the selected bank is larger than the RAM, the address wraps around.
+func (ctr *MBC5) ramAddr(addr uint16) int {
+	return (int(ctr.ramBank)*ramBankSize + int(addr-mbc5SwitchRAMStart)) % len(ctr.ram)
+}
+
+// Accepts returns true if the address is included in the ROM
+// or in the RAM, false otherwise.
+func (ctr *MBC5) Accepts(addr uint16) bool {
+	if addr >= mbc5SwitchRAMStart && addr <= mbc5SwitchRAMEnd && len(ctr.ram) == 0 {
+		return false
+	}
+
+	return (addr <= mbc5SwitchROMEnd) || (addr >= mbc5SwitchRAMStart && addr <= mbc5SwitchRAMEnd)
+}
@@ -0,0 +1,160 @@
+package cart
+
+import (
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+func TestNewMBC5(t *testing.T) {
+	t.Run("ROM is too small", func(t *testing.T) {
+		_, err := NewMBC5(make([]byte, 0), make([]byte, 0), false)
+		assert.Err(t, err, true)
+	})
+
+	t.Run("ROM is big enough", func(t *testing.T) {
+		_, err := NewMBC5(make([]byte, 2*romBankSize), make([]byte, 0), false)
+		assert.Err(t, err, false)
+	})
+}
+
+func TestMBC5_GetByte(t *testing.T) {
+	t.Run("ROM bank", func(t *testing.T) {
+		bytes := make([]byte, 2*romBankSize)
+		bytes[mbc5ROMBank0End] = 0x11
+
+		ctr, _ := NewMBC5(bytes, make([]byte, 0), false)
+
+		got, err := ctr.GetByte(mbc5ROMBank0End)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+	})
+
+	t.Run("RAM bank, RAM disabled", func(t *testing.T) {
+		ctr, _ := NewMBC5(make([]byte, 2*romBankSize), make([]byte, ramBankSize), false)
+
+		got, err := ctr.GetByte(mbc5SwitchRAMEnd)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0xFF))
+	})
+
+	t.Run("RAM bank, but no RAM", func(t *testing.T) {
+		ctr, _ := NewMBC5(make([]byte, 2*romBankSize), make([]byte, 0), false)
+
+		_, err := ctr.GetByte(mbc5SwitchRAMEnd)
+		assert.Err(t, err, true)
+	})
+}
+
+func TestMBC5_SetByte(t *testing.T) {
+	t.Run("Enable RAM", func(t *testing.T) {
+		ctr, _ := NewMBC5(make([]byte, 2*romBankSize), make([]byte, ramBankSize), false)
+
+		ctr.SetByte(mbc5RAMEnableStart, mbc5EnableRAMValue)
+		ctr.SetByte(mbc5SwitchRAMStart, 0x11)
+
+		got, err := ctr.GetByte(mbc5SwitchRAMStart)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+	})
+
+	t.Run("Switch RAM bank", func(t *testing.T) {
+		bytes := make([]byte, 16*ramBankSize)
+		bytes[16*ramBankSize-1] = 0x11
+
+		ctr, _ := NewMBC5(make([]byte, 2*romBankSize), bytes, false)
+		ctr.SetByte(mbc5RAMEnableStart, mbc5EnableRAMValue)
+		ctr.SetByte(mbc5RAMBankStart, 0x0F)
+
+		got, err := ctr.GetByte(mbc5SwitchRAMEnd)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+	})
+
+	t.Run("Switch 9 bit ROM bank", func(t *testing.T) {
+		bytes := make([]byte, 512*romBankSize)
+		bytes[0x102*romBankSize] = 0x11
+
+		ctr, _ := NewMBC5(bytes, make([]byte, 0), false)
+		ctr.SetByte(mbc5ROMBankLowStart, 0x02)
+		ctr.SetByte(mbc5ROMBankHighStart, 0x01)
+
+		got, err := ctr.GetByte(mbc5SwitchROMStart)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+		assert.Equal(t, ctr.ROMBank(), 0x102)
+	})
+
+	t.Run("ROM bank 0 in switchable area", func(t *testing.T) {
+		bytes := make([]byte, 2*romBankSize)
+		bytes[0x0000] = 0x11
+
+		ctr, _ := NewMBC5(bytes, make([]byte, 0), false)
+		ctr.SetByte(mbc5ROMBankLowStart, 0x00)
+
+		got, err := ctr.GetByte(mbc5SwitchROMStart)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x11))
+	})
+
+	t.Run("ROM bank larger than ROM wraps", func(t *testing.T) {
+		ctr, _ := NewMBC5(make([]byte, 4*romBankSize), make([]byte, 0), false)
+		ctr.SetByte(mbc5ROMBankHighStart, 0x01)
+		ctr.SetByte(mbc5ROMBankLowStart, 0x03)
+
+		assert.Equal(t, ctr.ROMBank(), 3)
+	})
+}
+
+func TestMBC5_SetRumble(t *testing.T) {
+	t.Run("rumble", func(t *testing.T) {
+		bytes := make([]byte, 16*ramBankSize)
+		bytes[ramBankSize] = 0x11
+
+		ctr, _ := NewMBC5(make([]byte, 2*romBankSize), bytes, true)
+		ctr.SetByte(mbc5RAMEnableStart, mbc5EnableRAMValue)
+
+		var got []bool
+		ctr.SetRumble(func(on bool) { got = append(got, on) })
+
+		ctr.SetByte(mbc5RAMBankStart, mbc5RumbleBit|0x01)
+		ctr.SetByte(mbc5RAMBankStart, mbc5RumbleBit|0x01)
+		ctr.SetByte(mbc5RAMBankStart, 0x01)
+		assert.Equal(t, got, []bool{true, false})
+
+		// The rumble bit does not select a RAM bank.
+		ctr.SetByte(mbc5RAMBankStart, mbc5RumbleBit|0x01)
+		b, err := ctr.GetByte(mbc5SwitchRAMStart)
+		assert.Err(t, err, false)
+		assert.Equal(t, b, byte(0x11))
+	})
+
+	t.Run("no rumble", func(t *testing.T) {
+		ctr, _ := NewMBC5(make([]byte, 2*romBankSize), make([]byte, 16*ramBankSize), false)
+
+		called := false
+		ctr.SetRumble(func(on bool) { called = true })
+		ctr.SetByte(mbc5RAMBankStart, mbc5RumbleBit)
+
+		assert.Equal(t, called, false)
+	})
+}
+
+func TestMBC5_Accepts(t *testing.T) {
+	t.Run("RAM address, but no RAM", func(t *testing.T) {
+		ctr, _ := NewMBC5(make([]byte, 2*romBankSize), make([]byte, 0), false)
+
+		got := ctr.Accepts(mbc5SwitchRAMStart)
+		assert.Equal(t, got, false)
+	})
+
+	t.Run("Outside mem", func(t *testing.T) {
+		ctr, _ := NewMBC5(make([]byte, 2*romBankSize), make([]byte, ramBankSize), false)
+
+		got := ctr.Accepts(0xFFFF)
+		assert.Equal(t, got, false)
+
+		got = ctr.Accepts(mbc5SwitchRAMEnd + 1)
+		assert.Equal(t, got, false)
+	})
+}
@@ -0,0 +1,69 @@
+package cart
+
+import "time"
+
+// Validation defines how the cartridge checksums and logo are validated.
+type Validation int
+
+// Validation modes.
+const (
+	// ValidateWarn logs a warning for each mismatch, and loads the cartridge anyway.
+	ValidateWarn Validation = iota
+	// ValidateReject refuses to load a cartridge with a mismatch.
+	ValidateReject
+	// ValidateIgnore does not validate the cartridge.
+	ValidateIgnore
+)
+
+// options contains the settings used to create a cartridge.
+type options struct {
+	validation  Validation
+	now         TimeSource
+	chooseEntry EntryChooser
+	patch       []byte
+}
+
+// Option changes the settings used to create a cartridge.
+type Option func(*options)
+
+// WithValidation sets how the cartridge checksums and logo are validated.
+// The default is ValidateWarn.
+func WithValidation(v Validation) Option {
+	return func(o *options) {
+		o.validation = v
+	}
+}
+
+// WithTimeSource sets the time source used by the cartridge clock.
+// The default is time.Now.
+func WithTimeSource(now TimeSource) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithEntry sets the function which chooses the ROM to load
+// from an archive containing several ROMs.
+// The default is to load the first ROM.
+func WithEntry(choose EntryChooser) Option {
+	return func(o *options) {
+		o.chooseEntry = choose
+	}
+}
+
+// WithPatch sets an IPS, UPS or BPS patch to apply to the ROM
+// before creating the cartridge. It is used only by Read and Open.
+func WithPatch(patch []byte) Option {
+	return func(o *options) {
+		o.patch = patch
+	}
+}
+
+// newOptions applies the given options to the default settings.
+func newOptions(opts []Option) *options {
+	o := &options{validation: ValidateWarn, now: time.Now, chooseEntry: firstEntry}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
@@ -0,0 +1,359 @@
+package cart
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// Magic bytes at the start of the supported patch formats.
+var (
+	ipsMagic = []byte("PATCH")
+	upsMagic = []byte("UPS1")
+	bpsMagic = []byte("BPS1")
+)
+
+// ipsEOF marks the end of the records of an IPS patch.
+var ipsEOF = []byte("EOF")
+
+// patchExts are the extensions of the patch files applied automatically
+// by Open, in order of preference.
+var patchExts = []string{".bps", ".ups", ".ips"}
+
+// Size of the UPS and BPS footer, which contains
+// the source, target and patch CRC32.
+const patchFooterSize = 12
+
+// maxTargetSize is the largest size of a patched ROM,
+// which is the largest ROM size allowed by the header.
+const maxTargetSize = 512 * romBankSize
+
+// Patch applies an IPS, UPS or BPS patch to the ROM, and returns the patched ROM.
+// The format is detected from the patch content. For UPS and BPS patches,
+// the CRC32 of the source and of the result are verified.
+func Patch(rom, patch []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(patch, ipsMagic):
+		return patchIPS(rom, patch)
+	case bytes.HasPrefix(patch, upsMagic):
+		return patchUPS(rom, patch)
+	case bytes.HasPrefix(patch, bpsMagic):
+		return patchBPS(rom, patch)
+	default:
+		return nil, errors.E("unknown patch format", errors.Cart)
+	}
+}
+
+// patchReader reads the values encoded in a patch,
+// recording the first read past the end of the patch.
+type patchReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+// bytes reads the next n bytes.
+func (r *patchReader) bytes(n int) []byte {
+	if r.err != nil || n < 0 || n > len(r.data)-r.pos {
+		if r.err == nil {
+			r.err = errors.E(fmt.Sprintf("patch truncated at offset %d", r.pos), errors.Cart)
+		}
+		return nil
+	}
+
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+// byte reads the next byte, or returns 0 if the patch is truncated.
+func (r *patchReader) byte() byte {
+	b := r.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+// uint reads a big endian unsigned integer of the given size, as used by IPS.
+func (r *patchReader) uint(size int) int {
+	v := 0
+	for _, b := range r.bytes(size) {
+		v = v<<8 | int(b)
+	}
+	return v
+}
+
+// varint reads a variable length integer, as used by UPS and BPS.
+func (r *patchReader) varint() int {
+	v, shift := 0, 1
+	for {
+		b := r.byte()
+		if r.err != nil {
+			return 0
+		}
+
+		v += int(b&0x7F) * shift
+		if b&0x80 != 0 {
+			return v
+		}
+
+		shift <<= 7
+		v += shift
+
+		if shift > 1<<42 {
+			r.err = errors.E(fmt.Sprintf("invalid number at offset %d", r.pos), errors.Cart)
+			return 0
+		}
+	}
+}
+
+// grow extends b with zeros up to the given size.
+func grow(b []byte, size int) []byte {
+	if size <= len(b) {
+		return b
+	}
+	return append(b, make([]byte, size-len(b))...)
+}
+
+// patchIPS applies an IPS patch, which contains a list of records that
+// overwrite the ROM bytes, followed by an optional truncation size.
+func patchIPS(rom, patch []byte) ([]byte, error) {
+	out := append([]byte{}, rom...)
+	r := &patchReader{data: patch, pos: len(ipsMagic)}
+
+	for {
+		if bytes.HasPrefix(patch[r.pos:], ipsEOF) {
+			r.pos += len(ipsEOF)
+			break
+		}
+
+		off := r.uint(3)
+		size := r.uint(2)
+
+		if size > 0 {
+			data := r.bytes(size)
+			if r.err != nil {
+				return nil, r.err
+			}
+
+			out = grow(out, off+size)
+			copy(out[off:], data)
+			continue
+		}
+
+		// A record with size 0 repeats a single byte.
+		size = r.uint(2)
+		value := r.byte()
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		out = grow(out, off+size)
+		for i := 0; i < size; i++ {
+			out[off+i] = value
+		}
+	}
+
+	if len(patch)-r.pos == 3 {
+		size := r.uint(3)
+		if size < len(out) {
+			out = out[:size]
+		}
+	}
+
+	return out, nil
+}
+
+// footer splits a UPS or BPS patch in its body and its CRCs,
+// verifying the patch CRC and the source CRC.
+func footer(rom, patch []byte) (body []byte, targetCRC uint32, err error) {
+	if len(patch) < patchFooterSize {
+		return nil, 0, errors.E("patch too small", errors.Cart)
+	}
+
+	body = patch[:len(patch)-patchFooterSize]
+	foot := patch[len(body):]
+	sourceCRC := binary.LittleEndian.Uint32(foot)
+	targetCRC = binary.LittleEndian.Uint32(foot[4:])
+	patchCRC := binary.LittleEndian.Uint32(foot[8:])
+
+	if got := crc32.ChecksumIEEE(patch[:len(patch)-4]); got != patchCRC {
+		return nil, 0, errors.E(fmt.Sprintf("patch crc is %08X, want %08X", got, patchCRC), errors.Cart)
+	}
+
+	if got := crc32.ChecksumIEEE(rom); got != sourceCRC {
+		return nil, 0, errors.E(fmt.Sprintf("rom crc is %08X, want %08X", got, sourceCRC), errors.Cart)
+	}
+
+	return body, targetCRC, nil
+}
+
+// checkTargetSize verifies that the size of the patched ROM
+// read from a patch is not too large to be allocated.
+func checkTargetSize(size int) error {
+	if size > maxTargetSize {
+		return errors.E(fmt.Sprintf("patched rom size is %d, the maximum is %d", size, maxTargetSize), errors.Cart)
+	}
+	return nil
+}
+
+// checkTarget verifies the CRC of the patched ROM.
+func checkTarget(out []byte, want uint32) error {
+	if got := crc32.ChecksumIEEE(out); got != want {
+		return errors.E(fmt.Sprintf("patched rom crc is %08X, want %08X", got, want), errors.Cart)
+	}
+	return nil
+}
+
+// patchUPS applies an UPS patch, which contains a list of
+// blocks that are XORed with the ROM bytes.
+func patchUPS(rom, patch []byte) ([]byte, error) {
+	body, targetCRC, err := footer(rom, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &patchReader{data: body, pos: len(upsMagic)}
+	sourceSize := r.varint()
+	targetSize := r.varint()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if sourceSize != len(rom) {
+		return nil, errors.E(fmt.Sprintf("rom size is %d, want %d", len(rom), sourceSize), errors.Cart)
+	}
+
+	if err := checkTargetSize(targetSize); err != nil {
+		return nil, err
+	}
+
+	out := grow(append([]byte{}, rom...), targetSize)[:targetSize]
+	for ptr := 0; r.pos < len(body); {
+		ptr += r.varint()
+
+		// Each block ends with a 0 byte, which is also applied.
+		for {
+			b := r.byte()
+			if r.err != nil {
+				return nil, r.err
+			}
+
+			if ptr < len(out) {
+				out[ptr] ^= b
+			}
+			ptr++
+
+			if b == 0 {
+				break
+			}
+		}
+	}
+
+	if err := checkTarget(out, targetCRC); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BPS actions.
+const (
+	bpsSourceRead = iota
+	bpsTargetRead
+	bpsSourceCopy
+	bpsTargetCopy
+)
+
+// patchBPS applies a BPS patch, which builds the patched ROM
+// with a list of actions that copy data from the ROM, from the patch or
+// from the already patched bytes.
+func patchBPS(rom, patch []byte) ([]byte, error) {
+	body, targetCRC, err := footer(rom, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &patchReader{data: body, pos: len(bpsMagic)}
+	sourceSize := r.varint()
+	targetSize := r.varint()
+	r.bytes(r.varint()) // Metadata
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if sourceSize != len(rom) {
+		return nil, errors.E(fmt.Sprintf("rom size is %d, want %d", len(rom), sourceSize), errors.Cart)
+	}
+
+	if err := checkTargetSize(targetSize); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, targetSize)
+	outPos, sourcePos, targetPos := 0, 0, 0
+
+	// relative reads a signed offset, encoded with the sign in the lowest bit.
+	relative := func() int {
+		d := r.varint()
+		if d&1 != 0 {
+			return -(d >> 1)
+		}
+		return d >> 1
+	}
+
+	for r.pos < len(body) {
+		data := r.varint()
+		action, length := data&3, data>>2+1
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		if outPos+length > len(out) {
+			return nil, errors.E(fmt.Sprintf("patch writes past the rom end at offset %d", r.pos), errors.Cart)
+		}
+
+		switch action {
+		case bpsSourceRead:
+			if outPos+length > len(rom) {
+				return nil, errors.E(fmt.Sprintf("patch reads past the rom end at offset %d", r.pos), errors.Cart)
+			}
+			copy(out[outPos:], rom[outPos:outPos+length])
+
+		case bpsTargetRead:
+			copy(out[outPos:], r.bytes(length))
+
+		case bpsSourceCopy:
+			sourcePos += relative()
+			if sourcePos < 0 || sourcePos+length > len(rom) {
+				return nil, errors.E(fmt.Sprintf("patch reads past the rom end at offset %d", r.pos), errors.Cart)
+			}
+			copy(out[outPos:], rom[sourcePos:sourcePos+length])
+			sourcePos += length
+
+		case bpsTargetCopy:
+			targetPos += relative()
+			if targetPos < 0 || targetPos >= outPos {
+				return nil, errors.E(fmt.Sprintf("patch copies unwritten bytes at offset %d", r.pos), errors.Cart)
+			}
+			// The copy can overlap with the bytes being written, so it is done byte by byte.
+			for i := 0; i < length; i++ {
+				out[outPos+i] = out[targetPos]
+				targetPos++
+			}
+		}
+
+		if r.err != nil {
+			return nil, r.err
+		}
+		outPos += length
+	}
+
+	if err := checkTarget(out, targetCRC); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
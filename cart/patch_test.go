@@ -0,0 +1,246 @@
+package cart
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+// varint encodes a number as a UPS and BPS variable length integer.
+func varint(v int) []byte {
+	var b []byte
+	for {
+		x := byte(v & 0x7F)
+		v >>= 7
+		if v == 0 {
+			return append(b, x|0x80)
+		}
+		b = append(b, x)
+		v--
+	}
+}
+
+// withFooter appends the source, target and patch CRC to a UPS or BPS patch.
+func withFooter(body, source, target []byte) []byte {
+	patch := append([]byte{}, body...)
+	patch = append(patch, make([]byte, 8)...)
+	binary.LittleEndian.PutUint32(patch[len(body):], crc32.ChecksumIEEE(source))
+	binary.LittleEndian.PutUint32(patch[len(body)+4:], crc32.ChecksumIEEE(target))
+
+	crc := make([]byte, 4)
+	binary.LittleEndian.PutUint32(crc, crc32.ChecksumIEEE(patch))
+	return append(patch, crc...)
+}
+
+// join concatenates the given byte slices.
+func join(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
+
+func TestPatch_IPS(t *testing.T) {
+	rom := []byte("ABCDEFGH")
+
+	t.Run("records", func(t *testing.T) {
+		patch := join(ipsMagic,
+			[]byte{0x00, 0x00, 0x02, 0x00, 0x02, 'x', 'y'},
+			[]byte{0x00, 0x00, 0x09, 0x00, 0x00, 0x00, 0x03, 'z'},
+			ipsEOF)
+
+		got, err := Patch(rom, patch)
+		assert.Err(t, err, false)
+		assert.Equal(t, string(got), "ABxyEFGH\x00zzz")
+		assert.Equal(t, string(rom), "ABCDEFGH")
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		patch := join(ipsMagic, ipsEOF, []byte{0x00, 0x00, 0x04})
+
+		got, err := Patch(rom, patch)
+		assert.Err(t, err, false)
+		assert.Equal(t, string(got), "ABCD")
+	})
+
+	t.Run("truncated record", func(t *testing.T) {
+		patch := join(ipsMagic, []byte{0x00, 0x00, 0x02, 0x00, 0x05, 'x'})
+
+		_, err := Patch(rom, patch)
+		assert.Err(t, err, true)
+	})
+
+	t.Run("missing EOF", func(t *testing.T) {
+		patch := join(ipsMagic, []byte{0x00, 0x00, 0x02, 0x00, 0x01, 'x'})
+
+		_, err := Patch(rom, patch)
+		assert.Err(t, err, true)
+	})
+}
+
+func TestPatch_UPS(t *testing.T) {
+	rom := []byte("ABCDEFGH")
+	target := []byte("AxyDEFGHIJ")
+
+	body := join(upsMagic, varint(len(rom)), varint(len(target)),
+		// Skip 1 byte, then XOR 2 bytes
+		varint(1), []byte{'B' ^ 'x', 'C' ^ 'y', 0x00},
+		// Skip 4 bytes, then write 2 bytes past the ROM end
+		varint(4), []byte{'I', 'J', 0x00})
+
+	t.Run("valid", func(t *testing.T) {
+		got, err := Patch(rom, withFooter(body, rom, target))
+		assert.Err(t, err, false)
+		assert.Equal(t, string(got), string(target))
+	})
+
+	t.Run("wrong ROM", func(t *testing.T) {
+		_, err := Patch([]byte("ABCDEFGX"), withFooter(body, rom, target))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("wrong target CRC", func(t *testing.T) {
+		_, err := Patch(rom, withFooter(body, rom, []byte("other")))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("corrupted patch", func(t *testing.T) {
+		patch := withFooter(body, rom, target)
+		patch[len(upsMagic)+3]++
+
+		_, err := Patch(rom, patch)
+		assert.Err(t, err, true)
+	})
+
+	t.Run("truncated block", func(t *testing.T) {
+		body := join(upsMagic, varint(len(rom)), varint(len(rom)), varint(1), []byte{'x'})
+
+		_, err := Patch(rom, withFooter(body, rom, rom))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("huge target size", func(t *testing.T) {
+		body := join(upsMagic, varint(len(rom)), varint(1<<40))
+
+		_, err := Patch(rom, withFooter(body, rom, rom))
+		assert.Err(t, err, true)
+	})
+}
+
+func TestPatch_BPS(t *testing.T) {
+	rom := []byte("ABCDEFGH")
+	target := []byte("ABXYEFABX")
+
+	action := func(action, length int) []byte {
+		return varint((length-1)<<2 | action)
+	}
+
+	body := join(bpsMagic, varint(len(rom)), varint(len(target)), varint(3), []byte("abc"),
+		action(bpsSourceRead, 2),
+		action(bpsTargetRead, 2), []byte("XY"),
+		action(bpsSourceCopy, 2), varint(4<<1),
+		action(bpsTargetCopy, 3), varint(0))
+
+	t.Run("valid", func(t *testing.T) {
+		got, err := Patch(rom, withFooter(body, rom, target))
+		assert.Err(t, err, false)
+		assert.Equal(t, string(got), string(target))
+	})
+
+	t.Run("wrong ROM", func(t *testing.T) {
+		_, err := Patch([]byte("ABCDEFGX"), withFooter(body, rom, target))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("wrong target CRC", func(t *testing.T) {
+		_, err := Patch(rom, withFooter(body, rom, []byte("other")))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("copy outside ROM", func(t *testing.T) {
+		body := join(bpsMagic, varint(len(rom)), varint(4), varint(0),
+			action(bpsSourceCopy, 4), varint(6<<1))
+
+		_, err := Patch(rom, withFooter(body, rom, rom[:4]))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("write past target", func(t *testing.T) {
+		body := join(bpsMagic, varint(len(rom)), varint(1), varint(0),
+			action(bpsSourceRead, 2))
+
+		_, err := Patch(rom, withFooter(body, rom, rom[:1]))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("too small", func(t *testing.T) {
+		_, err := Patch(rom, bpsMagic)
+		assert.Err(t, err, true)
+	})
+
+	t.Run("huge metadata size", func(t *testing.T) {
+		body := join(bpsMagic, varint(len(rom)), varint(len(rom)), varint(1<<40))
+
+		_, err := Patch(rom, withFooter(body, rom, rom))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("huge target size", func(t *testing.T) {
+		body := join(bpsMagic, varint(len(rom)), varint(1<<40), varint(0))
+
+		_, err := Patch(rom, withFooter(body, rom, rom))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("target size above limit", func(t *testing.T) {
+		body := join(bpsMagic, varint(len(rom)), varint(maxTargetSize+1), varint(0))
+
+		_, err := Patch(rom, withFooter(body, rom, rom))
+		assert.Err(t, err, true)
+	})
+}
+
+func TestPatch_Unknown(t *testing.T) {
+	_, err := Patch([]byte("ABCD"), []byte("NOPE"))
+	assert.Err(t, err, true)
+}
+
+func TestOpen_Patch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gameboy-patch")
+	assert.Err(t, err, false)
+	defer os.RemoveAll(dir)
+
+	rom := testROM("GAME")
+	assert.Err(t, ioutil.WriteFile(filepath.Join(dir, "game.gb"), rom, 0644), false)
+
+	patch := join(ipsMagic, []byte{0x00, 0x01, 0x34, 0x00, 0x04}, []byte("HACK"), ipsEOF)
+	assert.Err(t, ioutil.WriteFile(filepath.Join(dir, "game.ips"), patch, 0644), false)
+
+	t.Run("same-named patch", func(t *testing.T) {
+		c, err := Open(filepath.Join(dir, "game.gb"), WithValidation(ValidateIgnore))
+		assert.Err(t, err, false)
+		assert.Equal(t, c.Title(), "HACK")
+	})
+
+	t.Run("patch option", func(t *testing.T) {
+		patch := join(ipsMagic, []byte{0x00, 0x01, 0x34, 0x00, 0x04}, []byte("MINE"), ipsEOF)
+
+		c, err := Open(filepath.Join(dir, "game.gb"), WithValidation(ValidateIgnore), WithPatch(patch))
+		assert.Err(t, err, false)
+		assert.Equal(t, c.Title(), "MINE")
+	})
+
+	t.Run("invalid patch", func(t *testing.T) {
+		assert.Err(t, ioutil.WriteFile(filepath.Join(dir, "bad.gb"), rom, 0644), false)
+		assert.Err(t, ioutil.WriteFile(filepath.Join(dir, "bad.bps"), []byte("BPS1"), 0644), false)
+
+		_, err := Open(filepath.Join(dir, "bad.gb"))
+		assert.Err(t, err, true)
+	})
+}
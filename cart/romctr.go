@@ -51,7 +51,7 @@ func (ctr *ROMCtr) GetByte(addr uint16) (byte, error) {
 		return ctr.rom[addr], nil
 
 	case addr >= romCtrRAMStart && addr <= romCtrRAMEnd:
-		return ctr.ram[addr-romCtrRAMStart], nil
+		return ctr.ram[int(addr-romCtrRAMStart)%len(ctr.ram)], nil
 
 	default:
 		panic(fmt.Errorf("unhandled address %d in rom controller", addr))
@@ -71,7 +71,7 @@ func (ctr *ROMCtr) SetByte(addr uint16, value byte) error {
 		break
 
 	case addr >= romCtrRAMStart && addr <= romCtrRAMEnd:
-		ctr.ram[addr-romCtrRAMStart] = value
+		ctr.ram[int(addr-romCtrRAMStart)%len(ctr.ram)] = value
 
 	default:
 		panic(fmt.Errorf("unhandled address %d in rom controller", addr))
@@ -0,0 +1,222 @@
+package cart
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// RTC register numbers, selected by writing them to
+// the RAM bank register of an MBC3.
+const (
+	rtcSeconds  byte = 0x08
+	rtcMinutes  byte = 0x09
+	rtcHours    byte = 0x0A
+	rtcDaysLow  byte = 0x0B
+	rtcDaysHigh byte = 0x0C
+)
+
+// Bits of the high days register.
+const (
+	rtcDayBit   byte = 0x01
+	rtcHaltBit  byte = 0x40
+	rtcCarryBit byte = 0x80
+)
+
+// Number of seconds in each RTC unit.
+const (
+	secsPerMinute int64 = 60
+	secsPerHour   int64 = 60 * secsPerMinute
+	secsPerDay    int64 = 24 * secsPerHour
+	rtcMaxDays    int64 = 512
+)
+
+// TimeSource returns the current time.
+// It is used by the RTC to measure the elapsed time,
+// so that it can be replaced in tests.
+type TimeSource func() time.Time
+
+// rtcRegs are the values of the RTC registers.
+type rtcRegs struct {
+	seconds, minutes, hours, daysLow, daysHigh byte
+}
+
+// RTC implements the real time clock of an MBC3 cartridge.
+//
+// The clock counts seconds, minutes, hours and up to 511 days,
+// after which the day carry bit is set. The registers can be read
+// only after being latched.
+type RTC struct {
+	now     TimeSource
+	last    time.Time
+	regs    rtcRegs
+	latched rtcRegs
+}
+
+// NewRTC creates a new RTC which measures the time with
+// the given time source.
+func NewRTC(now TimeSource) *RTC {
+	return &RTC{now: now, last: now()}
+}
+
+// Latch copies the current time in the registers
+// returned by Get.
+func (r *RTC) Latch() {
+	r.update()
+	r.latched = r.regs
+}
+
+// Get returns the value of the given latched register.
+func (r *RTC) Get(reg byte) byte {
+	switch reg {
+	case rtcSeconds:
+		return r.latched.seconds
+	case rtcMinutes:
+		return r.latched.minutes
+	case rtcHours:
+		return r.latched.hours
+	case rtcDaysLow:
+		return r.latched.daysLow
+	case rtcDaysHigh:
+		return r.latched.daysHigh
+	default:
+		return 0xFF
+	}
+}
+
+// Set writes the given value in a register.
+// Unused bits of the register are ignored.
+func (r *RTC) Set(reg byte, value byte) {
+	r.update()
+
+	switch reg {
+	case rtcSeconds:
+		r.regs.seconds = value & 0x3F
+		// Writing the seconds resets the sub-second counter.
+		r.last = r.now()
+	case rtcMinutes:
+		r.regs.minutes = value & 0x3F
+	case rtcHours:
+		r.regs.hours = value & 0x1F
+	case rtcDaysLow:
+		r.regs.daysLow = value
+	case rtcDaysHigh:
+		r.regs.daysHigh = value & (rtcDayBit | rtcHaltBit | rtcCarryBit)
+	}
+}
+
+// halted returns true if the clock is stopped.
+func (r *RTC) halted() bool {
+	return r.regs.daysHigh&rtcHaltBit != 0
+}
+
+// update advances the registers by the whole seconds elapsed since
+// the last update, unless the clock is halted.
+func (r *RTC) update() {
+	now := r.now()
+	if r.halted() {
+		r.last = now
+		return
+	}
+
+	elapsed := int64(now.Sub(r.last) / time.Second)
+	if elapsed <= 0 {
+		return
+	}
+	r.last = r.last.Add(time.Duration(elapsed) * time.Second)
+	r.advance(elapsed)
+}
+
+// advance adds the given number of seconds to the registers,
+// setting the carry bit if the days counter overflows.
+func (r *RTC) advance(secs int64) {
+	seconds, carry := count(int64(r.regs.seconds), secs, 60, 64)
+	minutes, carry := count(int64(r.regs.minutes), carry, 60, 64)
+	hours, carry := count(int64(r.regs.hours), carry, 24, 32)
+
+	days := int64(r.regs.daysHigh&rtcDayBit)<<8 | int64(r.regs.daysLow)
+	days, carry = count(days, carry, rtcMaxDays, rtcMaxDays)
+	if carry > 0 {
+		r.regs.daysHigh |= rtcCarryBit
+	}
+
+	r.regs.seconds = byte(seconds)
+	r.regs.minutes = byte(minutes)
+	r.regs.hours = byte(hours)
+	r.regs.daysLow = byte(days)
+	r.regs.daysHigh = r.regs.daysHigh&^rtcDayBit | byte(days>>8)&rtcDayBit
+}
+
+// count adds n to a counter that wraps around at max, and returns the new
+// value and the number of times it wrapped around. As on the hardware,
+// a counter written with a value not lower than max keeps counting
+// up to the largest value allowed by its size, then goes back to 0
+// without carrying to the next counter.
+func count(value, n, max, size int64) (int64, int64) {
+	if value >= max {
+		if n < size-value {
+			return value + n, 0
+		}
+		n -= size - value
+		value = 0
+	}
+	return (value + n) % max, (value + n) / max
+}
+
+// Size of the RTC data appended to the save files, with a 64 bit timestamp
+// as written by BGB, or with a 32 bit timestamp as written by older emulators.
+const (
+	rtcSaveSize      = 48
+	rtcShortSaveSize = 44
+)
+
+// marshal encodes the registers and the time of the last update
+// in the format used by BGB and VBA-M: the current and the latched registers
+// as 32 bit little endian values, followed by a 64 bit UNIX timestamp.
+func (r *RTC) marshal() []byte {
+	r.update()
+
+	b := make([]byte, rtcSaveSize)
+	regs := []byte{
+		r.regs.seconds, r.regs.minutes, r.regs.hours, r.regs.daysLow, r.regs.daysHigh,
+		r.latched.seconds, r.latched.minutes, r.latched.hours, r.latched.daysLow, r.latched.daysHigh,
+	}
+	for i, v := range regs {
+		binary.LittleEndian.PutUint32(b[i*4:], uint32(v))
+	}
+	binary.LittleEndian.PutUint64(b[len(regs)*4:], uint64(r.last.Unix()))
+
+	return b
+}
+
+// unmarshal decodes the data written by marshal. The clock is advanced
+// by the time elapsed since the timestamp, unless it is halted.
+func (r *RTC) unmarshal(b []byte) error {
+	if len(b) != rtcSaveSize && len(b) != rtcShortSaveSize {
+		return errors.E(fmt.Sprintf("invalid rtc data size %d", len(b)), errors.Cart)
+	}
+
+	reg := func(i int) byte {
+		return byte(binary.LittleEndian.Uint32(b[i*4:]))
+	}
+	r.regs = rtcRegs{reg(0), reg(1), reg(2), reg(3), reg(4)}
+	r.latched = rtcRegs{reg(5), reg(6), reg(7), reg(8), reg(9)}
+
+	var ts int64
+	if len(b) == rtcSaveSize {
+		ts = int64(binary.LittleEndian.Uint64(b[40:]))
+	} else {
+		ts = int64(binary.LittleEndian.Uint32(b[40:]))
+	}
+
+	// The time elapsed is counted only if the timestamp is in the past.
+	r.last = time.Unix(ts, 0)
+	if now := r.now(); r.last.After(now) {
+		r.last = now
+	}
+	r.update()
+
+	return nil
+}
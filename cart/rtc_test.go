@@ -0,0 +1,160 @@
+package cart
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+// fakeClock is a time source which is advanced manually.
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{t: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestRTC_Latch(t *testing.T) {
+	clock := newFakeClock()
+	rtc := NewRTC(clock.now)
+
+	clock.advance(25*time.Hour + 2*time.Minute + 3*time.Second + 500*time.Millisecond)
+	rtc.Latch()
+
+	assert.Equal(t, rtc.Get(rtcSeconds), byte(3))
+	assert.Equal(t, rtc.Get(rtcMinutes), byte(2))
+	assert.Equal(t, rtc.Get(rtcHours), byte(1))
+	assert.Equal(t, rtc.Get(rtcDaysLow), byte(1))
+	assert.Equal(t, rtc.Get(rtcDaysHigh), byte(0))
+
+	t.Run("latched registers do not change", func(t *testing.T) {
+		clock.advance(10 * time.Second)
+		assert.Equal(t, rtc.Get(rtcSeconds), byte(3))
+	})
+
+	t.Run("sub-second time is kept", func(t *testing.T) {
+		clock.advance(500 * time.Millisecond)
+		rtc.Latch()
+		assert.Equal(t, rtc.Get(rtcSeconds), byte(14))
+	})
+}
+
+func TestRTC_Set(t *testing.T) {
+	t.Run("unused bits", func(t *testing.T) {
+		rtc := NewRTC(newFakeClock().now)
+		rtc.Set(rtcSeconds, 0xFF)
+		rtc.Set(rtcHours, 0xFF)
+		rtc.Set(rtcDaysHigh, 0xFF)
+		rtc.Latch()
+
+		assert.Equal(t, rtc.Get(rtcSeconds), byte(0x3F))
+		assert.Equal(t, rtc.Get(rtcHours), byte(0x1F))
+		assert.Equal(t, rtc.Get(rtcDaysHigh), byte(0xC1))
+	})
+
+	t.Run("halt", func(t *testing.T) {
+		clock := newFakeClock()
+		rtc := NewRTC(clock.now)
+
+		rtc.Set(rtcDaysHigh, rtcHaltBit)
+		clock.advance(time.Hour)
+		rtc.Latch()
+		assert.Equal(t, rtc.Get(rtcHours), byte(0))
+
+		rtc.Set(rtcDaysHigh, 0x00)
+		clock.advance(time.Minute)
+		rtc.Latch()
+		assert.Equal(t, rtc.Get(rtcHours), byte(0))
+		assert.Equal(t, rtc.Get(rtcMinutes), byte(1))
+	})
+
+	t.Run("invalid register", func(t *testing.T) {
+		rtc := NewRTC(newFakeClock().now)
+		rtc.Set(0x0D, 0x11)
+
+		assert.Equal(t, rtc.Get(0x0D), byte(0xFF))
+	})
+}
+
+func TestRTC_OutOfRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		reg         byte
+		value       byte
+		elapsed     time.Duration
+		want        byte
+		wantNextReg byte
+		wantNext    byte
+	}{
+		{"seconds keep counting", rtcSeconds, 60, time.Second, 61, rtcMinutes, 0},
+		{"seconds wrap without carry", rtcSeconds, 62, 3 * time.Second, 1, rtcMinutes, 0},
+		{"carry after wrap", rtcSeconds, 63, 61 * time.Second, 0, rtcMinutes, 1},
+		{"minutes wrap without carry", rtcMinutes, 61, 3 * time.Minute, 0, rtcHours, 0},
+		{"hours wrap without carry", rtcHours, 30, 2 * time.Hour, 0, rtcDaysLow, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			rtc := NewRTC(clock.now)
+
+			rtc.Set(tt.reg, tt.value)
+			clock.advance(tt.elapsed)
+			rtc.Latch()
+
+			assert.Equal(t, rtc.Get(tt.reg), tt.want)
+			assert.Equal(t, rtc.Get(tt.wantNextReg), tt.wantNext)
+		})
+	}
+}
+
+func TestRTC_DayCarry(t *testing.T) {
+	tests := []struct {
+		name     string
+		days     int
+		wantLow  byte
+		wantHigh byte
+	}{
+		{"day bit 8", 256, 0x00, rtcDayBit},
+		{"last day", 511, 0xFF, rtcDayBit},
+		{"overflow", 512, 0x00, rtcCarryBit},
+		{"after overflow", 513, 0x01, rtcCarryBit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			rtc := NewRTC(clock.now)
+
+			clock.advance(time.Duration(tt.days) * 24 * time.Hour)
+			rtc.Latch()
+
+			assert.Equal(t, rtc.Get(rtcDaysLow), tt.wantLow)
+			assert.Equal(t, rtc.Get(rtcDaysHigh), tt.wantHigh)
+		})
+	}
+
+	t.Run("carry is kept until cleared", func(t *testing.T) {
+		clock := newFakeClock()
+		rtc := NewRTC(clock.now)
+
+		clock.advance(512 * 24 * time.Hour)
+		clock.advance(24 * time.Hour)
+		rtc.Latch()
+		assert.Equal(t, rtc.Get(rtcDaysHigh), rtcCarryBit)
+
+		rtc.Set(rtcDaysHigh, 0x00)
+		rtc.Latch()
+		assert.Equal(t, rtc.Get(rtcDaysHigh), byte(0x00))
+	})
+}
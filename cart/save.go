@@ -0,0 +1,198 @@
+package cart
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// saveExt is the extension of the save files.
+const saveExt = ".sav"
+
+// SavePath returns the path of the save file of the given ROM,
+// which is in the same directory and has the .sav extension.
+func SavePath(romPath string) string {
+	return basePath(romPath) + saveExt
+}
+
+// basePath removes the extension from the path of a ROM.
+// The extension of a gzipped ROM is also removed.
+func basePath(romPath string) string {
+	p := strings.TrimSuffix(romPath, ".gz")
+	if p == romPath || isROMName(p) {
+		p = strings.TrimSuffix(p, filepath.Ext(p))
+	}
+	return p
+}
+
+// HasBattery returns true if the content of the cartridge RAM and clock
+// should be saved when the emulator is closed.
+func (c *Cart) HasBattery() bool {
+	return c.header.Type.HasBattery() && (len(c.ram) > 0 || c.rtc != nil)
+}
+
+// WriteSave writes the RAM content to w, followed
+// by the clock data if the cartridge has a clock.
+func (c *Cart) WriteSave(w io.Writer) error {
+	if _, err := w.Write(c.ram); err != nil {
+		return errors.E("write ram failed", err, errors.Cart)
+	}
+
+	if c.rtc != nil {
+		if _, err := w.Write(c.rtc.marshal()); err != nil {
+			return errors.E("write rtc failed", err, errors.Cart)
+		}
+	}
+	return nil
+}
+
+// ReadSave loads the RAM content, and the clock data if the cartridge
+// has a clock, from the data written by WriteSave.
+// A save without clock data is accepted.
+func (c *Cart) ReadSave(r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return errors.E("read save failed", err, errors.Cart)
+	}
+
+	if len(data) < len(c.ram) {
+		return errors.E("save is smaller than the cartridge ram", errors.Cart)
+	}
+	copy(c.ram, data)
+
+	if trailer := data[len(c.ram):]; c.rtc != nil && len(trailer) > 0 {
+		if err := c.rtc.unmarshal(trailer); err != nil {
+			return errors.E("read rtc failed", err, errors.Cart)
+		}
+	}
+	return nil
+}
+
+// Saver persists the battery backed RAM of a cartridge to a file.
+type Saver struct {
+	cart  *Cart
+	path  string
+	quiet time.Duration
+	now   TimeSource
+
+	// writes is the number of RAM writes at the last poll.
+	writes uint64
+	// lastWrite is the time when a RAM write was last seen.
+	lastWrite time.Time
+	// dirty is true if the RAM changed since the last save.
+	dirty bool
+}
+
+// NewSaver creates a new saver which writes the cartridge save to the given path.
+// When polled, the saver writes the save once no RAM writes
+// happened for the quiet duration.
+func NewSaver(c *Cart, path string, quiet time.Duration) *Saver {
+	return &Saver{cart: c, path: path, quiet: quiet, now: time.Now}
+}
+
+// Load reads the save file into the cartridge.
+// It does nothing if the file does not exist or the cartridge has no battery.
+func (s *Saver) Load() error {
+	if !s.cart.HasBattery() {
+		return nil
+	}
+
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.E("open save file failed", err, errors.Cart)
+	}
+	defer f.Close()
+
+	return s.cart.ReadSave(f)
+}
+
+// Poll checks for RAM writes, and writes the save file if the RAM
+// has changed and has not been written for the quiet duration.
+// It should be called periodically, for example once per frame.
+func (s *Saver) Poll() error {
+	if !s.cart.HasBattery() {
+		return nil
+	}
+
+	if s.cart.ramWrites != s.writes {
+		s.writes = s.cart.ramWrites
+		s.lastWrite = s.now()
+		s.dirty = true
+		return nil
+	}
+
+	if !s.dirty || s.now().Sub(s.lastWrite) < s.quiet {
+		return nil
+	}
+	return s.Save()
+}
+
+// Save writes the save file. The file is written atomically,
+// so an existing save is never left truncated.
+// It does nothing if the cartridge has no battery.
+func (s *Saver) Save() error {
+	if !s.cart.HasBattery() {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := s.cart.WriteSave(&buf); err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(s.path, buf.Bytes()); err != nil {
+		return errors.E("write save file failed", err, errors.Cart)
+	}
+
+	s.writes = s.cart.ramWrites
+	s.dirty = false
+	return nil
+}
+
+// writeFileAtomic writes the data to a temporary file in the same
+// directory as p, and then renames it to p.
+func writeFileAtomic(p string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(p), filepath.Base(p)+".tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	// Temporary files are created readable only by the owner.
+	if err := os.Chmod(tmp, 0644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, p); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package cart
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+// batteryCart creates a cartridge of the given type with an 8 KB RAM.
+func batteryCart(t *testing.T, cartType CartType, now TimeSource) *Cart {
+	t.Helper()
+
+	bytes := make([]byte, romCtrROMEnd+1)
+	bytes[cartTypeFlag] = byte(cartType)
+	bytes[ramSizeFlag] = valueRAMBank1
+
+	c, err := NewCart(bytes, WithValidation(ValidateIgnore), WithTimeSource(now))
+	assert.Err(t, err, false)
+
+	// Enable the RAM, if the controller needs it
+	c.SetByte(0x0000, 0x0A)
+	return c
+}
+
+func TestSavePath(t *testing.T) {
+	assert.Equal(t, SavePath(filepath.Join("roms", "game.gb")), filepath.Join("roms", "game.sav"))
+	assert.Equal(t, SavePath("game"), "game.sav")
+	assert.Equal(t, SavePath("game.zip"), "game.sav")
+	assert.Equal(t, SavePath("game.gb.gz"), "game.sav")
+	assert.Equal(t, SavePath("game.gz"), "game.sav")
+}
+
+func TestCart_HasBattery(t *testing.T) {
+	assert.Equal(t, batteryCart(t, TypeMBC1RAMBattery, time.Now).HasBattery(), true)
+	assert.Equal(t, batteryCart(t, TypeMBC1RAM, time.Now).HasBattery(), false)
+	assert.Equal(t, batteryCart(t, TypeMBC2Battery, time.Now).HasBattery(), true)
+}
+
+func TestCart_WriteSave(t *testing.T) {
+	t.Run("RAM only", func(t *testing.T) {
+		c := batteryCart(t, TypeMBC1RAMBattery, time.Now)
+		c.SetByte(ramStart, 0x11)
+
+		var buf bytes.Buffer
+		err := c.WriteSave(&buf)
+		assert.Err(t, err, false)
+		assert.Equal(t, buf.Len(), ramBankSize)
+		assert.Equal(t, buf.Bytes()[0], byte(0x11))
+	})
+
+	t.Run("MBC2 RAM", func(t *testing.T) {
+		c := batteryCart(t, TypeMBC2Battery, time.Now)
+
+		var buf bytes.Buffer
+		err := c.WriteSave(&buf)
+		assert.Err(t, err, false)
+		assert.Equal(t, buf.Len(), mbc2RAMSize)
+	})
+
+	t.Run("RAM and RTC", func(t *testing.T) {
+		clock := newFakeClock()
+		c := batteryCart(t, TypeMBC3TimerRAMBattery, clock.now)
+		clock.advance(3 * time.Second)
+
+		var buf bytes.Buffer
+		err := c.WriteSave(&buf)
+		assert.Err(t, err, false)
+
+		b := buf.Bytes()
+		assert.Equal(t, len(b), ramBankSize+rtcSaveSize)
+		// Current seconds
+		assert.Equal(t, b[ramBankSize:ramBankSize+4], []byte{3, 0, 0, 0})
+		// Timestamp
+		assert.Equal(t, b[ramBankSize+40:], []byte{0x03, 0xE1, 0x0B, 0x5E, 0, 0, 0, 0})
+	})
+}
+
+func TestCart_ReadSave(t *testing.T) {
+	t.Run("RAM only", func(t *testing.T) {
+		c := batteryCart(t, TypeMBC1RAMBattery, time.Now)
+		data := make([]byte, ramBankSize)
+		data[0] = 0x11
+
+		err := c.ReadSave(bytes.NewReader(data))
+		assert.Err(t, err, false)
+
+		got, _ := c.GetByte(ramStart)
+		assert.Equal(t, got, byte(0x11))
+	})
+
+	t.Run("save too small", func(t *testing.T) {
+		c := batteryCart(t, TypeMBC1RAMBattery, time.Now)
+
+		err := c.ReadSave(bytes.NewReader(make([]byte, 10)))
+		assert.Err(t, err, true)
+	})
+
+	t.Run("RTC advances by elapsed time", func(t *testing.T) {
+		clock := newFakeClock()
+		saved := batteryCart(t, TypeMBC3TimerRAMBattery, clock.now)
+		saved.SetByte(mbc3RAMBankStart, rtcMinutes)
+		saved.SetByte(ramStart, 10)
+
+		var buf bytes.Buffer
+		saved.WriteSave(&buf)
+
+		clock.advance(5 * time.Minute)
+		c := batteryCart(t, TypeMBC3TimerRAMBattery, clock.now)
+		err := c.ReadSave(&buf)
+		assert.Err(t, err, false)
+
+		c.SetByte(mbc3RAMBankStart, rtcMinutes)
+		c.SetByte(mbc3LatchStart, 0x00)
+		c.SetByte(mbc3LatchStart, 0x01)
+		got, _ := c.GetByte(ramStart)
+		assert.Equal(t, got, byte(15))
+	})
+
+	t.Run("RTC with 32 bit timestamp", func(t *testing.T) {
+		clock := newFakeClock()
+		c := batteryCart(t, TypeMBC3TimerBattery, clock.now)
+
+		data := make([]byte, ramBankSize+rtcShortSaveSize)
+		data[ramBankSize+8] = 2
+		copy(data[ramBankSize+40:], []byte{0x00, 0xE1, 0x0B, 0x5E})
+
+		err := c.ReadSave(bytes.NewReader(data))
+		assert.Err(t, err, false)
+		assert.Equal(t, c.rtc.regs.hours, byte(2))
+		assert.Equal(t, c.rtc.regs.seconds, byte(0))
+	})
+
+	t.Run("invalid RTC", func(t *testing.T) {
+		c := batteryCart(t, TypeMBC3TimerBattery, time.Now)
+
+		err := c.ReadSave(bytes.NewReader(make([]byte, ramBankSize+10)))
+		assert.Err(t, err, true)
+	})
+}
+
+func TestSaver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gameboy-save")
+	assert.Err(t, err, false)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "game.sav")
+	clock := newFakeClock()
+
+	c := batteryCart(t, TypeMBC1RAMBattery, clock.now)
+	s := NewSaver(c, path, time.Second)
+	s.now = clock.now
+
+	t.Run("no save file", func(t *testing.T) {
+		err := s.Load()
+		assert.Err(t, err, false)
+	})
+
+	t.Run("not saved while writing", func(t *testing.T) {
+		c.SetByte(ramStart, 0x11)
+		assert.Err(t, s.Poll(), false)
+
+		clock.advance(500 * time.Millisecond)
+		c.SetByte(ramStart, 0x22)
+		assert.Err(t, s.Poll(), false)
+
+		clock.advance(500 * time.Millisecond)
+		assert.Err(t, s.Poll(), false)
+
+		_, err := os.Stat(path)
+		assert.Equal(t, os.IsNotExist(err), true)
+	})
+
+	t.Run("saved when quiet", func(t *testing.T) {
+		clock.advance(500 * time.Millisecond)
+		assert.Err(t, s.Poll(), false)
+
+		data, err := ioutil.ReadFile(path)
+		assert.Err(t, err, false)
+		assert.Equal(t, data[0], byte(0x22))
+
+		files, _ := ioutil.ReadDir(dir)
+		assert.Equal(t, len(files), 1)
+	})
+
+	t.Run("load", func(t *testing.T) {
+		loaded := batteryCart(t, TypeMBC1RAMBattery, clock.now)
+		err := NewSaver(loaded, path, time.Second).Load()
+		assert.Err(t, err, false)
+
+		got, _ := loaded.GetByte(ramStart)
+		assert.Equal(t, got, byte(0x22))
+	})
+
+	t.Run("no battery", func(t *testing.T) {
+		noBattery := batteryCart(t, TypeMBC1RAM, clock.now)
+		p := filepath.Join(dir, "none.sav")
+
+		err := NewSaver(noBattery, p, time.Second).Save()
+		assert.Err(t, err, false)
+
+		_, err = os.Stat(p)
+		assert.Equal(t, os.IsNotExist(err), true)
+	})
+}
@@ -2,47 +2,107 @@ package cart
 
 import (
 	"bytes"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
 
 	"github.com/lucactt/gameboy/util/errors"
+	"github.com/rs/zerolog/log"
 )
 
 // Open reads a file and creates a new cartridge
-// with its content.
-func Open(p string) (*Cart, error) {
-	bytes, err := ioutil.ReadFile(p)
+// with its content, using the given options.
+// The file can be a ROM, or a zip or gzip archive containing a ROM.
+//
+// If a patch file with the same name as the ROM and the .bps, .ups or .ips
+// extension exists, it is applied to the ROM, unless a patch is given in the options.
+func Open(p string, opts ...Option) (*Cart, error) {
+	f, err := os.Open(p)
 	if err != nil {
-		return nil, errors.E("read cartridge file failed", err, errors.Cart)
+		return nil, errors.E("open cartridge file failed", err, errors.Cart)
 	}
+	defer f.Close()
 
-	return NewCart(bytes)
+	patch, err := findPatch(p)
+	if err != nil {
+		return nil, errors.E("read patch file failed", err, errors.Cart)
+	}
+	if patch != nil {
+		opts = append([]Option{WithPatch(patch)}, opts...)
+	}
+
+	return Read(f, opts...)
 }
 
-// controller wraps a rom with the controller specified by the cart type flag.
-func controller(rom []byte, ram []byte) (Controller, error) {
-	t := rom[cartTypeFlag]
+// findPatch reads the patch file next to the given ROM,
+// or returns nil if there is none.
+func findPatch(romPath string) ([]byte, error) {
+	for _, ext := range patchExts {
+		p := basePath(romPath) + ext
 
-	switch {
-	case t == 0x00 || t == 0x08 || t == 0x09:
-		return NewROMCtr(rom, ram)
-	default:
-		return nil, errors.E("unsupported cartridge type", errors.Cart)
+		patch, err := ioutil.ReadFile(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		log.Info().Str("patch", p).Msg("patch found")
+		return patch, nil
+	}
+	return nil, nil
+}
+
+// Read reads r until EOF and creates a new cartridge with its content,
+// using the given options.
+// The content can be a ROM, or a zip or gzip archive containing a ROM.
+// If a patch is given in the options, it is applied to the ROM.
+func Read(r io.Reader, opts ...Option) (*Cart, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.E("read cartridge failed", err, errors.Cart)
+	}
+
+	o := newOptions(opts)
+	rom, err := unarchive(data, o.chooseEntry)
+	if err != nil {
+		return nil, errors.E("extract rom failed", err, errors.Cart)
 	}
+
+	if o.patch != nil {
+		if rom, err = Patch(rom, o.patch); err != nil {
+			return nil, errors.E("apply patch failed", err, errors.Cart)
+		}
+	}
+
+	return NewCart(rom, opts...)
 }
 
-// ramBanks reads the number of RAM banks.
-func ramBanks(rom []byte) int {
-	switch rom[ramSizeFlag] {
-	case valueRAMBank1:
-		return 1
-	case valueRAMBank4:
-		return 4
-	case valueRAMBank16:
-		return 16
-	case valueRAMBank8:
-		return 8
+// controller wraps a rom with the controller specified by the cart type.
+// The RTC is used only by the cart types with a timer.
+func controller(t CartType, rom []byte, ram []byte, rtc *RTC) (Controller, error) {
+	switch t {
+	case TypeROM, TypeROMRAM, TypeROMRAMBattery:
+		return NewROMCtr(rom, ram)
+	case TypeMBC1, TypeMBC1RAM, TypeMBC1RAMBattery:
+		if isMBC1M(rom) {
+			return NewMBC1M(rom, ram)
+		}
+		return NewMBC1(rom, ram)
+	case TypeMBC2, TypeMBC2Battery:
+		return NewMBC2(rom)
+	case TypeMBC3TimerBattery, TypeMBC3TimerRAMBattery:
+		return NewMBC3(rom, ram, rtc)
+	case TypeMBC3, TypeMBC3RAM, TypeMBC3RAMBattery:
+		return NewMBC3(rom, ram, nil)
+	case TypeMBC5, TypeMBC5RAM, TypeMBC5RAMBattery:
+		return NewMBC5(rom, ram, false)
+	case TypeMBC5Rumble, TypeMBC5RumbleRAM, TypeMBC5RumbleRAMBattery:
+		return NewMBC5(rom, ram, true)
 	default:
-		return 0
+		return nil, errors.E(fmt.Sprintf("unsupported cartridge type %s", t), errors.Cart)
 	}
 }
 
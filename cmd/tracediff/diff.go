@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/lucactt/gameboy/disasm"
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// fields are the fields of a trace line, in order.
+var fields = []string{"A", "F", "B", "C", "D", "E", "H", "L", "SP", "PC", "PCMEM"}
+
+// errMismatch is returned by the differ to stop the CPU at the first mismatch.
+var errMismatch = errors.E("trace mismatch")
+
+// state is the CPU state described by a trace line, by field name.
+type state map[string]string
+
+// parseLine parses a line in the Gameboy Doctor format.
+func parseLine(line string) (state, error) {
+	s := state{}
+	for _, f := range strings.Fields(line) {
+		kv := strings.SplitN(f, ":", 2)
+		if len(kv) != 2 {
+			return nil, errors.E(fmt.Sprintf("invalid field %q", f))
+		}
+		s[kv[0]] = strings.ToUpper(kv[1])
+	}
+
+	for _, f := range fields {
+		if _, ok := s[f]; !ok {
+			return nil, errors.E(fmt.Sprintf("missing field %s", f))
+		}
+	}
+	return s, nil
+}
+
+// flags formats the F register of a state as the set flags, e.g. "Z-H-".
+func (s state) flags() string {
+	f, err := strconv.ParseUint(s["F"], 16, 8)
+	if err != nil {
+		return "????"
+	}
+
+	res := []byte("----")
+	for i, name := range "ZNHC" {
+		if f&(0x80>>uint(i)) != 0 {
+			res[i] = byte(name)
+		}
+	}
+	return string(res)
+}
+
+// mismatch is the first line where the traces diverge.
+type mismatch struct {
+	line      int
+	got, want string
+	// last is the last matching line, which describes
+	// the instruction run before the mismatch.
+	last string
+	// context are the matching lines before the mismatch,
+	// and after are the reference lines that follow it.
+	context, after []string
+}
+
+// differ is a writer that compares each trace line written
+// by the CPU with the next line of a reference trace.
+type differ struct {
+	ref     *bufio.Scanner
+	line    int
+	last    string
+	context []string
+	size    int
+
+	mismatch *mismatch
+	done     bool
+}
+
+// newDiffer creates a differ that shows the given number of
+// lines before and after the mismatch as context.
+func newDiffer(ref io.Reader, context int) *differ {
+	return &differ{ref: bufio.NewScanner(ref), size: context}
+}
+
+// Write compares a trace line. It returns errMismatch when the
+// line doesn't match the reference, or io.EOF when the reference ends.
+func (d *differ) Write(p []byte) (int, error) {
+	got := strings.TrimSpace(string(p))
+
+	if !d.ref.Scan() {
+		d.done = true
+		if err := d.ref.Err(); err != nil {
+			return 0, err
+		}
+		return 0, io.EOF
+	}
+	d.line++
+	want := strings.TrimSpace(d.ref.Text())
+
+	if !equal(got, want) {
+		d.mismatch = &mismatch{line: d.line, got: got, want: want, last: d.last, context: d.context}
+		for len(d.mismatch.after) < d.size && d.ref.Scan() {
+			d.mismatch.after = append(d.mismatch.after, strings.TrimSpace(d.ref.Text()))
+		}
+		return 0, errMismatch
+	}
+
+	d.last = got
+	d.context = append(d.context, got)
+	if len(d.context) > d.size {
+		d.context = d.context[1:]
+	}
+	return len(p), nil
+}
+
+// lastInstr disassembles the instruction run before the mismatch,
+// from the bytes recorded in the PCMEM field of the last matching line.
+// The bytes are the ones read when the instruction ran, so they are
+// right even if the code was changed or its bank switched after it.
+func (m *mismatch) lastInstr() string {
+	s, err := parseLine(m.last)
+	if err != nil {
+		return ""
+	}
+	pc, err := strconv.ParseUint(s["PC"], 16, 16)
+	if err != nil {
+		return ""
+	}
+
+	var bytes []byte
+	for _, b := range strings.Split(s["PCMEM"], ",") {
+		v, err := strconv.ParseUint(b, 16, 8)
+		if err != nil {
+			return ""
+		}
+		bytes = append(bytes, byte(v))
+	}
+
+	instr, err := disasm.DecodeBytes(bytes, uint16(pc))
+	if err != nil {
+		return ""
+	}
+	return instr.Loc.String() + " " + instr.String()
+}
+
+// equal compares two trace lines, ignoring the case of hex digits.
+func equal(got, want string) bool {
+	return strings.EqualFold(got, want)
+}
+
+// report writes a description of the mismatch, with the
+// given instruction being the one run before it.
+// The context lines after the mismatch are from the reference trace.
+func (m *mismatch) report(w io.Writer, instr string) {
+	fmt.Fprintf(w, "traces diverge at line %d\n\n", m.line)
+
+	for _, c := range m.context {
+		fmt.Fprintf(w, "  %s\n", c)
+	}
+	fmt.Fprintf(w, "- %s\n+ %s\n", m.want, m.got)
+	for _, a := range m.after {
+		fmt.Fprintf(w, "  %s\n", a)
+	}
+	fmt.Fprintln(w)
+
+	if instr != "" {
+		fmt.Fprintf(w, "last instruction: %s\n", instr)
+	}
+
+	got, errGot := parseLine(m.got)
+	want, errWant := parseLine(m.want)
+	if errGot != nil || errWant != nil {
+		fmt.Fprintf(w, "reference line can't be parsed\n")
+		return
+	}
+
+	for _, f := range fields {
+		if got[f] == want[f] {
+			continue
+		}
+		fmt.Fprintf(w, "%-5s got %s, want %s\n", f, got[f], want[f])
+		if f == "F" {
+			fmt.Fprintf(w, "%-5s got %s, want %s\n", "flags", got.flags(), want.flags())
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+const (
+	line1 = "A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0100 PCMEM:00,C3,13,02"
+	line2 = "A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0101 PCMEM:C3,13,02,00"
+	line3 = "A:01 F:80 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0213 PCMEM:00,00,00,00"
+)
+
+func TestParseLine(t *testing.T) {
+	t.Run("valid line", func(t *testing.T) {
+		got, err := parseLine(line1)
+		assert.Err(t, err, false)
+		assert.Equal(t, got["PC"], "0100")
+		assert.Equal(t, got["PCMEM"], "00,C3,13,02")
+		assert.Equal(t, got.flags(), "Z-HC")
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		_, err := parseLine("A:01 F:B0")
+		assert.Err(t, err, true)
+	})
+
+	t.Run("invalid field", func(t *testing.T) {
+		_, err := parseLine("A01")
+		assert.Err(t, err, true)
+	})
+}
+
+func TestDiffer_Write(t *testing.T) {
+	t.Run("match", func(t *testing.T) {
+		d := newDiffer(strings.NewReader(line1+"\n"+line2+"\n"), 5)
+
+		_, err := d.Write([]byte(line1 + "\n"))
+		assert.Err(t, err, false)
+		_, err = d.Write([]byte(strings.ToLower(line2) + "\n"))
+		assert.Err(t, err, false)
+
+		_, err = d.Write([]byte(line1 + "\n"))
+		assert.Equal(t, err, io.EOF)
+		assert.Equal(t, d.done, true)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		d := newDiffer(strings.NewReader(line1+"\n"+line2+"\n"+line3+"\n"), 1)
+
+		d.Write([]byte(line1 + "\n"))
+		d.Write([]byte(line2 + "\n"))
+		_, err := d.Write([]byte(strings.Replace(line3, "F:80", "F:00", 1) + "\n"))
+
+		assert.Equal(t, err, error(errMismatch))
+		assert.Equal(t, d.mismatch.line, 3)
+		assert.Equal(t, d.mismatch.last, line2)
+		assert.Equal(t, d.mismatch.context, []string{line2})
+	})
+
+	t.Run("context after mismatch", func(t *testing.T) {
+		d := newDiffer(strings.NewReader(line1+"\n"+line2+"\n"+line3+"\n"), 1)
+
+		_, err := d.Write([]byte(line2 + "\n"))
+
+		assert.Equal(t, err, error(errMismatch))
+		assert.Equal(t, d.mismatch.after, []string{line2})
+	})
+}
+
+func TestMismatch_report(t *testing.T) {
+	m := &mismatch{
+		line:    2,
+		got:     strings.Replace(line2, "F:B0", "F:00", 1),
+		want:    line2,
+		context: []string{line1},
+		after:   []string{line3},
+	}
+
+	var buf bytes.Buffer
+	m.report(&buf, "00:0100 nop")
+
+	out := buf.String()
+	for _, want := range []string{
+		"traces diverge at line 2",
+		"  " + line1,
+		"+ " + m.got + "\n  " + line3,
+		"last instruction: 00:0100 nop",
+		"F     got 00, want B0",
+		"flags got ----, want Z-HC",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report doesn't contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMismatch_lastInstr(t *testing.T) {
+	t.Run("instruction from PCMEM", func(t *testing.T) {
+		m := &mismatch{last: line2}
+		assert.Equal(t, m.lastInstr(), "00:0101 jp $0213")
+	})
+
+	t.Run("no matching line", func(t *testing.T) {
+		m := &mismatch{}
+		assert.Equal(t, m.lastInstr(), "")
+	})
+}
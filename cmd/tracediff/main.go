@@ -0,0 +1,57 @@
+// Command tracediff runs a ROM and compares its instruction trace
+// with a reference trace in the Gameboy Doctor format, stopping
+// at the first line where they diverge.
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+
+	"github.com/lucactt/gameboy/cpu"
+	"github.com/lucactt/gameboy/system"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	romPath := flag.String("rom", "", "path of the ROM to run")
+	refPath := flag.String("ref", "", "path of the reference trace")
+	n := flag.Int("n", 1000000, "maximum number of instructions to run")
+	context := flag.Int("context", 5, "number of lines shown before and after the mismatch")
+	flag.Parse()
+
+	output := zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: "15:04"}
+	log.Logger = zerolog.New(output).With().Timestamp().Logger()
+
+	gb, err := system.Open(*romPath, ioutil.Discard)
+	if err != nil {
+		log.Fatal().Err(err).Msg("load ROM failed")
+	}
+
+	ref, err := os.Open(*refPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("open reference trace failed")
+	}
+	defer ref.Close()
+
+	d := newDiffer(ref, *context)
+	gb.CPU.Tracer = cpu.NewTracer(d, nil)
+
+	for i := 0; i < *n; i++ {
+		if _, err := gb.CPU.Tick(); err != nil {
+			switch {
+			case d.mismatch != nil:
+				d.mismatch.report(os.Stdout, d.mismatch.lastInstr())
+				os.Exit(1)
+			case d.done:
+				log.Info().Int("instructions", i).Msg("reference trace ended, no divergence found")
+				return
+			default:
+				log.Fatal().Err(err).Msg("CPU stopped")
+			}
+		}
+	}
+
+	log.Info().Int("instructions", *n).Msg("no divergence found")
+}
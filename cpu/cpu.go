@@ -2,38 +2,191 @@
 package cpu
 
 import (
+	"fmt"
+
 	"github.com/lucactt/gameboy/mem"
 	"github.com/lucactt/gameboy/util/errors"
 )
 
+const (
+	// cbPrefix is the opcode that selects the CB-prefixed instruction set.
+	cbPrefix byte = 0xCB
+
+	// idleCycles is the number of cycles used by a tick
+	// while the CPU is halted or stopped.
+	idleCycles int = 4
+)
+
 // CPU represents a GameBoy CPU.
 type CPU struct {
-	Mem      mem.Mem
-	Regs     *Regs
-	StateMgr *StateMgr
-	InstrSet *InstrSet
+	Mem        mem.Mem
+	Regs       *Regs
+	StateMgr   *StateMgr
+	InstrSet   *InstrSet
+	Interrupts *Interrupts
+
+	// Tracer writes the state of the CPU before each instruction.
+	// Tracing is disabled if it's nil.
+	Tracer *Tracer
+
+	// pause is true if Pause was called during the current tick.
+	pause bool
 }
 
 // New creates a new CPU.
+//
+// The IE and IF registers of the CPU interrupts
+// are not mapped to the given memory, so they should be
+// added to it by the caller.
 func New(mem mem.Mem) *CPU {
 	regs := NewRegs()
 	stateMgr := NewStateMgr()
 	instrSet := NewInstrSet(regs, mem, stateMgr)
+	interrupts := NewInterrupts()
+
+	return &CPU{Mem: mem, Regs: regs, StateMgr: stateMgr, InstrSet: instrSet, Interrupts: interrupts}
+}
 
-	return &CPU{mem, regs, stateMgr, instrSet}
+// Pause asks the CPU to pause after the current instruction.
+// It is meant to be called while an instruction is running,
+// for example by a memory watchpoint.
+func (c *CPU) Pause() {
+	c.pause = true
 }
 
 // Tick runs the instruction found in the memory at the address contained in PC,
 // and returns the number of clock cycles used by that instruction.
+//
+// If an interrupt is pending and interrupts are enabled,
+// the interrupt is serviced instead. While the CPU is halted or stopped,
+// no instruction is run, but cycles are still used.
+//
+// If the instruction fails, the registers and the CPU state are
+// restored and a *errors.Error that wraps a *Fault is returned.
+// The memory is not restored, but the instructions that write two bytes
+// check both addresses first, so an unmapped address doesn't
+// leave a partial write.
+//
+// If Pause is called during the tick, the instruction is completed and
+// an error with code Paused is returned. Calling Tick again resumes the execution.
 func (c *CPU) Tick() (int, error) {
+	cycles, err := c.tick()
+	if c.pause {
+		c.pause = false
+		if err == nil {
+			return cycles, errors.E("paused by request", Paused, errors.CPU)
+		}
+	}
+	return cycles, err
+}
+
+// tick runs an instruction or services an interrupt, as described by Tick.
+func (c *CPU) tick() (int, error) {
+	if c.Interrupts.Pending() {
+		// A pending interrupt wakes up the CPU even if IME is disabled.
+		if c.StateMgr.State() == Halted {
+			c.StateMgr.SetState(Running)
+		}
+
+		if c.StateMgr.InterruptsEnabled() {
+			return c.serviceInterrupt()
+		}
+	}
+
+	switch c.StateMgr.State() {
+	case Halted:
+		return idleCycles, nil
+	case Stopped:
+		// Only a joypad press can end the STOP mode.
+		if !c.Interrupts.requested(Joypad) {
+			return idleCycles, nil
+		}
+		c.StateMgr.SetState(Running)
+	}
+
+	if c.Tracer != nil {
+		if err := c.Tracer.trace(c); err != nil {
+			return 0, errors.E("write trace failed", err, errors.CPU)
+		}
+	}
+
+	// The state is saved to restore it if the instruction fails.
+	regs := *c.Regs
+	stateMgr := *c.StateMgr
+
 	pc := c.Regs.PC.HiLo()
 	opCode, err := c.Mem.GetByte(pc)
 	if err != nil {
-		return 0, errors.E("get opcode failed", err, errors.CPU)
+		return 0, fault("get opcode failed", errors.E("read addr failed", UnmappedAddr, err, errors.CPU), pc, 0, regs)
 	}
 
-	len, cycles := c.InstrSet.NoPrefix[opCode]()
-	c.Regs.PC.Set(pc + uint16(len))
+	if c.StateMgr.haltBug {
+		// PC is not incremented after fetching the opcode,
+		// so the byte after HALT is read twice.
+		c.StateMgr.haltBug = false
+		c.Regs.PC.Set(pc - 1)
+	}
+
+	instr := c.InstrSet.NoPrefix[opCode]
+	if opCode == cbPrefix {
+		cbOpCode, err := c.Mem.GetByte(c.Regs.PC.HiLo() + 1)
+		if err != nil {
+			*c.Regs = regs
+			*c.StateMgr = stateMgr
+			return 0, fault("get CB opcode failed", errors.E("read addr failed", UnmappedAddr, err, errors.CPU), pc, opCode, regs)
+		}
+		instr = c.InstrSet.CBPrefix[cbOpCode]
+	}
+
+	len, cycles := instr()
+	if err := c.InstrSet.takeErr(); err != nil {
+		*c.Regs = regs
+		*c.StateMgr = stateMgr
+		return 0, fault("run instruction failed", err, pc, opCode, regs)
+	}
+
+	// PC is read again because jump instructions change it,
+	// taking into account the length that is added here.
+	c.Regs.PC.Set(c.Regs.PC.HiLo() + uint16(len))
+	c.StateMgr.step()
+
+	// When HALT is run with IME disabled and an interrupt already pending,
+	// the CPU doesn't halt and the HALT bug is triggered.
+	if c.StateMgr.State() == Halted && !c.StateMgr.InterruptsEnabled() && c.Interrupts.Pending() {
+		c.StateMgr.SetState(Running)
+		c.StateMgr.haltBug = true
+	}
 
 	return cycles, nil
 }
+
+// serviceInterrupt disables interrupts, pushes PC on the stack
+// and jumps to the vector of the pending interrupt with the highest priority.
+func (c *CPU) serviceInterrupt() (int, error) {
+	n, _ := c.Interrupts.next()
+
+	pc := c.Regs.PC.HiLo()
+	sp := c.Regs.SP.HiLo()
+
+	// Both addresses are checked before writing,
+	// so that a failed dispatch doesn't change the stack.
+	for _, addr := range []uint16{sp - 1, sp - 2} {
+		if !c.Mem.Accepts(addr) {
+			return 0, fault("dispatch interrupt failed", errors.E(fmt.Sprintf("write addr %#04x failed", addr), UnmappedAddr, errors.CPU), pc, 0, *c.Regs)
+		}
+	}
+
+	if err := c.Mem.SetByte(sp-1, byte(pc>>8)); err != nil {
+		return 0, fault("dispatch interrupt failed", errors.E("write addr failed", UnmappedAddr, err, errors.CPU), pc, 0, *c.Regs)
+	}
+	if err := c.Mem.SetByte(sp-2, byte(pc&0xFF)); err != nil {
+		return 0, fault("dispatch interrupt failed", errors.E("write addr failed", UnmappedAddr, err, errors.CPU), pc, 0, *c.Regs)
+	}
+
+	c.StateMgr.SetIME(false)
+	c.Interrupts.ack(n)
+	c.Regs.SP.Set(sp - 2)
+	c.Regs.PC.Set(n.Vector())
+
+	return interruptCycles, nil
+}
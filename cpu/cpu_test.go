@@ -0,0 +1,307 @@
+package cpu
+
+import (
+	"testing"
+
+	"github.com/lucactt/gameboy/mem"
+	"github.com/lucactt/gameboy/util/assert"
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+func TestCPU_Tick(t *testing.T) {
+	t.Run("advances PC", func(t *testing.T) {
+		ram := mem.NewRAM(0xFFFF)
+		c := New(ram)
+
+		// LD B,d8
+		ram.SetByte(0x0100, 0x06)
+		ram.SetByte(0x0101, 0x11)
+
+		cycles, err := c.Tick()
+
+		assert.Err(t, err, false)
+		assert.Equal(t, c.Regs.BC.Hi(), byte(0x11))
+		assert.Equal(t, c.Regs.PC.HiLo(), uint16(0x0102))
+		assert.Equal(t, cycles, 8)
+	})
+
+	t.Run("jumps", func(t *testing.T) {
+		ram := mem.NewRAM(0xFFFF)
+		c := New(ram)
+
+		// JP 0x0150
+		ram.SetByte(0x0100, 0xC3)
+		ram.SetByte(0x0101, 0x50)
+		ram.SetByte(0x0102, 0x01)
+
+		cycles, err := c.Tick()
+
+		assert.Err(t, err, false)
+		assert.Equal(t, c.Regs.PC.HiLo(), uint16(0x0150))
+		assert.Equal(t, cycles, 16)
+	})
+
+	t.Run("CB prefix", func(t *testing.T) {
+		ram := mem.NewRAM(0xFFFF)
+		c := New(ram)
+
+		// SET 0,(HL)
+		c.Regs.HL.Set(0x2000)
+		ram.SetByte(0x0100, 0xCB)
+		ram.SetByte(0x0101, 0xC6)
+
+		cycles, err := c.Tick()
+
+		got, _ := ram.GetByte(0x2000)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x01))
+		assert.Equal(t, c.Regs.PC.HiLo(), uint16(0x0102))
+		assert.Equal(t, cycles, 16)
+	})
+
+	t.Run("opcode outside memory", func(t *testing.T) {
+		c := New(mem.NewRAM(0))
+
+		_, err := c.Tick()
+		assert.Err(t, err, true)
+	})
+}
+
+func TestCPU_Tick_interrupts(t *testing.T) {
+	t.Run("dispatch", func(t *testing.T) {
+		ram := mem.NewRAM(0xFFFF)
+		c := New(ram)
+
+		c.Interrupts.IE().Write(0x1F)
+		c.Interrupts.Request(Serial)
+		c.Interrupts.Request(Timer)
+
+		cycles, err := c.Tick()
+
+		lo, _ := ram.GetByte(0xFFFC)
+		hi, _ := ram.GetByte(0xFFFD)
+		flag := c.Interrupts.IF().Read()
+		assert.Err(t, err, false)
+		assert.Equal(t, cycles, 20)
+		assert.Equal(t, c.Regs.PC.HiLo(), Timer.Vector())
+		assert.Equal(t, c.Regs.SP.HiLo(), uint16(0xFFFC))
+		assert.Equal(t, lo, byte(0x00))
+		assert.Equal(t, hi, byte(0x01))
+		assert.Equal(t, c.StateMgr.ime, false)
+		assert.Equal(t, flag&0x1F, byte(0x08))
+	})
+
+	t.Run("IME disabled", func(t *testing.T) {
+		ram := mem.NewRAM(0xFFFF)
+		c := New(ram)
+
+		c.StateMgr.SetIME(false)
+		c.Interrupts.IE().Write(0x1F)
+		c.Interrupts.Request(VBlank)
+
+		c.Tick()
+
+		assert.Equal(t, c.Regs.PC.HiLo(), uint16(0x0101))
+		assert.Equal(t, c.Interrupts.Pending(), true)
+	})
+
+	t.Run("partially unmapped stack", func(t *testing.T) {
+		ram := mem.NewRAM(0xF000)
+		c := New(ram)
+
+		c.Regs.SP.Set(0xF001)
+		c.Interrupts.IE().Write(0x1F)
+		c.Interrupts.Request(VBlank)
+
+		_, err := c.Tick()
+
+		got, _ := ram.GetByte(0xEFFF)
+		assert.Err(t, err, true)
+		assert.Equal(t, got, byte(0x00))
+		assert.Equal(t, c.Regs.SP.HiLo(), uint16(0xF001))
+	})
+
+	t.Run("wake from HALT", func(t *testing.T) {
+		ram := mem.NewRAM(0xFFFF)
+		c := New(ram)
+
+		c.StateMgr.SetIME(false)
+		c.StateMgr.SetState(Halted)
+		c.Interrupts.IE().Write(0x1F)
+		c.Interrupts.Request(VBlank)
+
+		c.Tick()
+
+		assert.Equal(t, c.StateMgr.current, Running)
+	})
+}
+
+func TestCPU_Tick_states(t *testing.T) {
+	t.Run("halted", func(t *testing.T) {
+		ram := mem.NewRAM(0xFFFF)
+		c := New(ram)
+
+		// HALT
+		ram.SetByte(0x0100, 0x76)
+
+		c.Tick()
+		cycles, err := c.Tick()
+
+		assert.Err(t, err, false)
+		assert.Equal(t, c.StateMgr.State(), Halted)
+		assert.Equal(t, c.Regs.PC.HiLo(), uint16(0x0101))
+		assert.Equal(t, cycles, 4)
+	})
+
+	t.Run("stopped until joypad", func(t *testing.T) {
+		ram := mem.NewRAM(0xFFFF)
+		c := New(ram)
+
+		// STOP
+		ram.SetByte(0x0100, 0x10)
+
+		c.Tick()
+		c.Tick()
+		assert.Equal(t, c.StateMgr.State(), Stopped)
+		assert.Equal(t, c.Regs.PC.HiLo(), uint16(0x0102))
+
+		c.Interrupts.Request(Joypad)
+		c.Tick()
+		assert.Equal(t, c.StateMgr.State(), Running)
+		assert.Equal(t, c.Regs.PC.HiLo(), uint16(0x0103))
+	})
+
+	t.Run("EI delay", func(t *testing.T) {
+		ram := mem.NewRAM(0xFFFF)
+		c := New(ram)
+
+		// EI, NOP
+		c.StateMgr.SetIME(false)
+		ram.SetByte(0x0100, 0xFB)
+		c.Interrupts.IE().Write(0x01)
+		c.Interrupts.Request(VBlank)
+
+		c.Tick()
+		assert.Equal(t, c.Regs.PC.HiLo(), uint16(0x0101))
+
+		c.Tick()
+		assert.Equal(t, c.Regs.PC.HiLo(), uint16(0x0102))
+
+		c.Tick()
+		assert.Equal(t, c.Regs.PC.HiLo(), VBlank.Vector())
+	})
+
+	t.Run("HALT bug", func(t *testing.T) {
+		ram := mem.NewRAM(0xFFFF)
+		c := New(ram)
+
+		// HALT, LD A,d8 (0x14), INC D
+		c.StateMgr.SetIME(false)
+		ram.SetByte(0x0100, 0x76)
+		ram.SetByte(0x0101, 0x3E)
+		ram.SetByte(0x0102, 0x14)
+		c.Interrupts.IE().Write(0x01)
+		c.Interrupts.Request(VBlank)
+
+		c.Tick()
+		assert.Equal(t, c.StateMgr.State(), Running)
+
+		// The byte after HALT is read twice, so A is loaded with
+		// the opcode itself and the next instruction is INC D.
+		c.Tick()
+		assert.Equal(t, c.Regs.AF.Hi(), byte(0x3E))
+		assert.Equal(t, c.Regs.PC.HiLo(), uint16(0x0102))
+
+		d := c.Regs.DE.Hi()
+		c.Tick()
+		assert.Equal(t, c.Regs.DE.Hi(), d+1)
+	})
+}
+
+func TestCPU_Tick_faults(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     []byte
+		sp       uint16
+		wantCode errors.ErrCode
+	}{
+		{"illegal opcode", []byte{0xD3}, 0xFFFE, IllegalOpCode},
+		{"stack underflow", []byte{0xC9}, 0xFFFF, StackUnderflow},
+		{"unmapped address", []byte{0xFA, 0x00, 0xF0}, 0xFFFE, UnmappedAddr},
+		{"unmapped stack", []byte{0xC5}, 0xF001, UnmappedAddr},
+		{"partially unmapped address", []byte{0x08, 0xFF, 0xEF}, 0xFFFE, UnmappedAddr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ram := mem.NewRAM(0xF000)
+			c := New(ram)
+
+			c.Regs.SP.Set(tt.sp)
+			for i, b := range tt.code {
+				ram.SetByte(0x0100+uint16(i), b)
+			}
+			want := *c.Regs
+
+			_, err := c.Tick()
+			assert.Err(t, err, true)
+
+			e, ok := err.(*errors.Error)
+			assert.Equal(t, ok, true)
+			assert.Equal(t, e.Component, errors.CPU)
+			assert.Equal(t, e.Code, tt.wantCode)
+
+			f, ok := e.Err.(*Fault)
+			assert.Equal(t, ok, true)
+			assert.Equal(t, f.PC, uint16(0x0100))
+			assert.Equal(t, f.OpCode, tt.code[0])
+			assert.Equal(t, f.Regs, want)
+
+			// The registers must not be changed by the failed instruction.
+			assert.Equal(t, *c.Regs, want)
+
+			// The mapped half of a 16 bit write must not be written.
+			got, _ := ram.GetByte(0xEFFF)
+			assert.Equal(t, got, byte(0x00))
+		})
+	}
+}
+
+func TestCPU_Pause(t *testing.T) {
+	ram := mem.NewRAM(0xFFFF)
+	w := mem.NewWatch(ram)
+	c := New(w)
+	w.PC = c.Regs.PC.HiLo
+	w.Pause = c.Pause
+
+	var got mem.Event
+	w.Add(mem.Watchpoint{Start: 0xC000, End: 0xC000, Access: mem.Write, Hook: func(e mem.Event) bool {
+		got = e
+		return true
+	}})
+
+	// LD A,d8; LD (a16),A; NOP
+	for i, b := range []byte{0x3E, 0x42, 0xEA, 0x00, 0xC0, 0x00} {
+		ram.SetByte(0x0100+uint16(i), b)
+	}
+
+	_, err := c.Tick()
+	assert.Err(t, err, false)
+
+	cycles, err := c.Tick()
+	assert.Err(t, err, true)
+	e, ok := err.(*errors.Error)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, e.Code, Paused)
+	assert.Equal(t, cycles, 16)
+	assert.Equal(t, got, mem.Event{Addr: 0xC000, Value: 0x42, Access: mem.Write, PC: 0x0102})
+
+	// The paused instruction is completed, not rolled back.
+	value, _ := ram.GetByte(0xC000)
+	assert.Equal(t, value, byte(0x42))
+	assert.Equal(t, c.Regs.PC.HiLo(), uint16(0x0105))
+
+	_, err = c.Tick()
+	assert.Err(t, err, false)
+	assert.Equal(t, c.Regs.PC.HiLo(), uint16(0x0106))
+}
@@ -0,0 +1,47 @@
+package cpu
+
+import (
+	"fmt"
+
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// Codes of the errors returned by the CPU.
+const (
+	UnmappedAddr errors.ErrCode = iota + 1
+	IllegalOpCode
+	StackUnderflow
+	// Paused is not a fault, and is returned when the CPU pauses on request.
+	Paused
+)
+
+// Fault describes the state of the CPU when
+// an instruction or an interrupt dispatch failed.
+//
+// The registers are the ones before the instruction was run,
+// which are also restored in the CPU.
+type Fault struct {
+	PC     uint16
+	OpCode byte
+	Regs   Regs
+	Err    error
+}
+
+func (f *Fault) Error() string {
+	return fmt.Sprintf("PC:%04X opcode:%02X AF:%04X BC:%04X DE:%04X HL:%04X SP:%04X: %v",
+		f.PC, f.OpCode,
+		f.Regs.AF.HiLo(), f.Regs.BC.HiLo(), f.Regs.DE.HiLo(), f.Regs.HL.HiLo(), f.Regs.SP.HiLo(),
+		f.Err)
+}
+
+// Unwrap returns the wrapped error.
+func (f *Fault) Unwrap() error {
+	return f.Err
+}
+
+// fault wraps the error in a Fault with the given state,
+// and returns it as a CPU error with the same code.
+func fault(msg string, err *errors.Error, pc uint16, opCode byte, regs Regs) *errors.Error {
+	f := &Fault{PC: pc, OpCode: opCode, Regs: regs, Err: err}
+	return errors.E(msg, err.Code, f, errors.CPU)
+}
@@ -2,6 +2,7 @@ package cpu
 
 import (
 	"github.com/lucactt/gameboy/mem"
+	"github.com/lucactt/gameboy/util/errors"
 )
 
 // Instr is a Gameboy CPU instruction, which consists in a function
@@ -10,17 +11,23 @@ type Instr func() (int, int)
 
 // InstrSet contains both the non-prefixed and CB-prefixed instructions
 // supported by the Gameboy CPU.
+//
+// The length and cycles returned by the CB-prefixed instructions
+// include the ones of the 0xCB prefix.
 type InstrSet struct {
 	NoPrefix []Instr
 	CBPrefix []Instr
+
+	util *instrUtil
 }
 
 // NewInstrSet creates a new instruction set that reads and writes to the
 // given registers and memory.
 func NewInstrSet(regs *Regs, mem mem.Mem, stateMgr *StateMgr) *InstrSet {
-	util := &instrUtil{regs, mem}
+	util := &instrUtil{regs: regs, mem: mem}
 
 	return &InstrSet{
+		util: util,
 		NoPrefix: []Instr{
 			func() (int, int) {
 				// 0x00 - NOP
@@ -62,7 +69,7 @@ func NewInstrSet(regs *Regs, mem mem.Mem, stateMgr *StateMgr) *InstrSet {
 				original := regs.AF.Hi()
 				regs.AF.SetHi((original << 1) | (original >> 7))
 
-				regs.SetZ(regs.AF.Hi() == 0)
+				regs.SetZ(false)
 				regs.SetN(false)
 				regs.SetH(false)
 
@@ -75,8 +82,10 @@ func NewInstrSet(regs *Regs, mem mem.Mem, stateMgr *StateMgr) *InstrSet {
 
 				// Compose the 16 bit address from the two 8 bit parts.
 				addr := uint16(util.getByteAtPC(2))<<8 | uint16(util.getByteAtPC(1))
-				util.setByte(addr, regs.SP.Lo())
-				util.setByte(addr+1, regs.SP.Hi())
+				if util.canWrite(addr) && util.canWrite(addr+1) {
+					util.setByte(addr, regs.SP.Lo())
+					util.setByte(addr+1, regs.SP.Hi())
+				}
 				return 3, 20
 			},
 			func() (int, int) {
@@ -109,7 +118,7 @@ func NewInstrSet(regs *Regs, mem mem.Mem, stateMgr *StateMgr) *InstrSet {
 				original := regs.AF.Hi()
 				regs.AF.SetHi((original >> 1) | (original << 7))
 
-				regs.SetZ(regs.AF.Hi() == 0)
+				regs.SetZ(false)
 				regs.SetN(false)
 				regs.SetH(false)
 
@@ -158,7 +167,7 @@ func NewInstrSet(regs *Regs, mem mem.Mem, stateMgr *StateMgr) *InstrSet {
 				}
 				regs.AF.SetHi((original << 1) + carry)
 
-				regs.SetZ(regs.AF.Hi() == 0)
+				regs.SetZ(false)
 				regs.SetN(false)
 				regs.SetH(false)
 
@@ -168,8 +177,7 @@ func NewInstrSet(regs *Regs, mem mem.Mem, stateMgr *StateMgr) *InstrSet {
 			},
 			func() (int, int) {
 				// 0x18 - JR r8
-				regs.PC.Set(regs.PC.HiLo() + uint16(util.getByteAtPC(1)))
-				return 2, 12
+				return util.jr(true)
 			},
 			func() (int, int) {
 				// 0x19 - ADD HL,DE
@@ -205,7 +213,7 @@ func NewInstrSet(regs *Regs, mem mem.Mem, stateMgr *StateMgr) *InstrSet {
 				}
 				regs.AF.SetHi((original >> 1) | (carry << 7))
 
-				regs.SetZ(regs.AF.Hi() == 0)
+				regs.SetZ(false)
 				regs.SetN(false)
 				regs.SetH(false)
 
@@ -215,10 +223,7 @@ func NewInstrSet(regs *Regs, mem mem.Mem, stateMgr *StateMgr) *InstrSet {
 			},
 			func() (int, int) {
 				// 0x20 - JR NZ,r8
-				if !regs.Z() {
-					regs.PC.Set(regs.PC.HiLo() + uint16(util.getByteAtPC(1)))
-				}
-				return 2, 8
+				return util.jr(!regs.Z())
 			},
 			func() (int, int) {
 				// 0x21 - LD HL,d16
@@ -276,11 +281,8 @@ func NewInstrSet(regs *Regs, mem mem.Mem, stateMgr *StateMgr) *InstrSet {
 				return 1, 4
 			},
 			func() (int, int) {
-				// 0x28 - JR Z, r8
-				if regs.Z() {
-					regs.PC.Set(regs.PC.HiLo() + uint16(util.getByteAtPC(1)))
-				}
-				return 2, 8
+				// 0x28 - JR Z,r8
+				return util.jr(regs.Z())
 			},
 			func() (int, int) {
 				// 0x29 - ADD HL,HL
@@ -316,6 +318,1997 @@ func NewInstrSet(regs *Regs, mem mem.Mem, stateMgr *StateMgr) *InstrSet {
 
 				return 1, 4
 			},
-		},
-	}
+			func() (int, int) {
+				// 0x30 - JR NC,r8
+				return util.jr(!regs.C())
+			},
+			func() (int, int) {
+				// 0x31 - LD SP,d16
+				regs.SP.Set(util.getD16AtPC())
+				return 3, 12
+			},
+			func() (int, int) {
+				// 0x32 - LD (HL-),A
+				util.setByte(regs.HL.HiLo(), regs.AF.Hi())
+				util.dec16(regs.HL.HiLo(), regs.HL.Set)
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x33 - INC SP
+				return util.inc16(regs.SP.HiLo(), regs.SP.Set)
+			},
+			func() (int, int) {
+				// 0x34 - INC (HL)
+				addr := regs.HL.HiLo()
+				util.inc8(util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 1, 12
+			},
+			func() (int, int) {
+				// 0x35 - DEC (HL)
+				addr := regs.HL.HiLo()
+				util.dec8(util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 1, 12
+			},
+			func() (int, int) {
+				// 0x36 - LD (HL),d8
+				util.setByte(regs.HL.HiLo(), util.getByteAtPC(1))
+				return 2, 12
+			},
+			func() (int, int) {
+				// 0x37 - SCF
+				regs.SetN(false)
+				regs.SetH(false)
+				regs.SetC(true)
+				return 1, 4
+			},
+			func() (int, int) {
+				// 0x38 - JR C,r8
+				return util.jr(regs.C())
+			},
+			func() (int, int) {
+				// 0x39 - ADD HL,SP
+				return util.add16(regs.HL.HiLo(), regs.SP.HiLo(), func(res uint16) { regs.HL.Set(res) })
+			},
+			func() (int, int) {
+				// 0x3A - LD A,(HL-)
+				regs.AF.SetHi(util.getByte(regs.HL.HiLo()))
+				util.dec16(regs.HL.HiLo(), regs.HL.Set)
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x3B - DEC SP
+				return util.dec16(regs.SP.HiLo(), regs.SP.Set)
+			},
+			func() (int, int) {
+				// 0x3C - INC A
+				return util.inc8(regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0x3D - DEC A
+				return util.dec8(regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0x3E - LD A,d8
+				return util.ld8d8(regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0x3F - CCF
+				regs.SetN(false)
+				regs.SetH(false)
+				regs.SetC(!regs.C())
+				return 1, 4
+			},
+			func() (int, int) {
+				// 0x40 - LD B,B
+				return util.ld8(regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0x41 - LD B,C
+				return util.ld8(regs.BC.Lo(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0x42 - LD B,D
+				return util.ld8(regs.DE.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0x43 - LD B,E
+				return util.ld8(regs.DE.Lo(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0x44 - LD B,H
+				return util.ld8(regs.HL.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0x45 - LD B,L
+				return util.ld8(regs.HL.Lo(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0x46 - LD B,(HL)
+				regs.BC.SetHi(util.getByte(regs.HL.HiLo()))
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x47 - LD B,A
+				return util.ld8(regs.AF.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0x48 - LD C,B
+				return util.ld8(regs.BC.Hi(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0x49 - LD C,C
+				return util.ld8(regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0x4A - LD C,D
+				return util.ld8(regs.DE.Hi(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0x4B - LD C,E
+				return util.ld8(regs.DE.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0x4C - LD C,H
+				return util.ld8(regs.HL.Hi(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0x4D - LD C,L
+				return util.ld8(regs.HL.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0x4E - LD C,(HL)
+				regs.BC.SetLo(util.getByte(regs.HL.HiLo()))
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x4F - LD C,A
+				return util.ld8(regs.AF.Hi(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0x50 - LD D,B
+				return util.ld8(regs.BC.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0x51 - LD D,C
+				return util.ld8(regs.BC.Lo(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0x52 - LD D,D
+				return util.ld8(regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0x53 - LD D,E
+				return util.ld8(regs.DE.Lo(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0x54 - LD D,H
+				return util.ld8(regs.HL.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0x55 - LD D,L
+				return util.ld8(regs.HL.Lo(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0x56 - LD D,(HL)
+				regs.DE.SetHi(util.getByte(regs.HL.HiLo()))
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x57 - LD D,A
+				return util.ld8(regs.AF.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0x58 - LD E,B
+				return util.ld8(regs.BC.Hi(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0x59 - LD E,C
+				return util.ld8(regs.BC.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0x5A - LD E,D
+				return util.ld8(regs.DE.Hi(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0x5B - LD E,E
+				return util.ld8(regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0x5C - LD E,H
+				return util.ld8(regs.HL.Hi(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0x5D - LD E,L
+				return util.ld8(regs.HL.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0x5E - LD E,(HL)
+				regs.DE.SetLo(util.getByte(regs.HL.HiLo()))
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x5F - LD E,A
+				return util.ld8(regs.AF.Hi(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0x60 - LD H,B
+				return util.ld8(regs.BC.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0x61 - LD H,C
+				return util.ld8(regs.BC.Lo(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0x62 - LD H,D
+				return util.ld8(regs.DE.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0x63 - LD H,E
+				return util.ld8(regs.DE.Lo(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0x64 - LD H,H
+				return util.ld8(regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0x65 - LD H,L
+				return util.ld8(regs.HL.Lo(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0x66 - LD H,(HL)
+				regs.HL.SetHi(util.getByte(regs.HL.HiLo()))
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x67 - LD H,A
+				return util.ld8(regs.AF.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0x68 - LD L,B
+				return util.ld8(regs.BC.Hi(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0x69 - LD L,C
+				return util.ld8(regs.BC.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0x6A - LD L,D
+				return util.ld8(regs.DE.Hi(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0x6B - LD L,E
+				return util.ld8(regs.DE.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0x6C - LD L,H
+				return util.ld8(regs.HL.Hi(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0x6D - LD L,L
+				return util.ld8(regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0x6E - LD L,(HL)
+				regs.HL.SetLo(util.getByte(regs.HL.HiLo()))
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x6F - LD L,A
+				return util.ld8(regs.AF.Hi(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0x70 - LD (HL),B
+				util.setByte(regs.HL.HiLo(), regs.BC.Hi())
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x71 - LD (HL),C
+				util.setByte(regs.HL.HiLo(), regs.BC.Lo())
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x72 - LD (HL),D
+				util.setByte(regs.HL.HiLo(), regs.DE.Hi())
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x73 - LD (HL),E
+				util.setByte(regs.HL.HiLo(), regs.DE.Lo())
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x74 - LD (HL),H
+				util.setByte(regs.HL.HiLo(), regs.HL.Hi())
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x75 - LD (HL),L
+				util.setByte(regs.HL.HiLo(), regs.HL.Lo())
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x76 - HALT
+
+				// The HALT bug is handled by the CPU, which checks
+				// for pending interrupts after running this.
+				stateMgr.SetState(Halted)
+				return 1, 4
+			},
+			func() (int, int) {
+				// 0x77 - LD (HL),A
+				util.setByte(regs.HL.HiLo(), regs.AF.Hi())
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x78 - LD A,B
+				return util.ld8(regs.BC.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0x79 - LD A,C
+				return util.ld8(regs.BC.Lo(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0x7A - LD A,D
+				return util.ld8(regs.DE.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0x7B - LD A,E
+				return util.ld8(regs.DE.Lo(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0x7C - LD A,H
+				return util.ld8(regs.HL.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0x7D - LD A,L
+				return util.ld8(regs.HL.Lo(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0x7E - LD A,(HL)
+				regs.AF.SetHi(util.getByte(regs.HL.HiLo()))
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x7F - LD A,A
+				return util.ld8(regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0x80 - ADD A,B
+				return util.add8(regs.BC.Hi())
+			},
+			func() (int, int) {
+				// 0x81 - ADD A,C
+				return util.add8(regs.BC.Lo())
+			},
+			func() (int, int) {
+				// 0x82 - ADD A,D
+				return util.add8(regs.DE.Hi())
+			},
+			func() (int, int) {
+				// 0x83 - ADD A,E
+				return util.add8(regs.DE.Lo())
+			},
+			func() (int, int) {
+				// 0x84 - ADD A,H
+				return util.add8(regs.HL.Hi())
+			},
+			func() (int, int) {
+				// 0x85 - ADD A,L
+				return util.add8(regs.HL.Lo())
+			},
+			func() (int, int) {
+				// 0x86 - ADD A,(HL)
+				util.add8(util.getByte(regs.HL.HiLo()))
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x87 - ADD A,A
+				return util.add8(regs.AF.Hi())
+			},
+			func() (int, int) {
+				// 0x88 - ADC A,B
+				return util.adc8(regs.BC.Hi(), true)
+			},
+			func() (int, int) {
+				// 0x89 - ADC A,C
+				return util.adc8(regs.BC.Lo(), true)
+			},
+			func() (int, int) {
+				// 0x8A - ADC A,D
+				return util.adc8(regs.DE.Hi(), true)
+			},
+			func() (int, int) {
+				// 0x8B - ADC A,E
+				return util.adc8(regs.DE.Lo(), true)
+			},
+			func() (int, int) {
+				// 0x8C - ADC A,H
+				return util.adc8(regs.HL.Hi(), true)
+			},
+			func() (int, int) {
+				// 0x8D - ADC A,L
+				return util.adc8(regs.HL.Lo(), true)
+			},
+			func() (int, int) {
+				// 0x8E - ADC A,(HL)
+				util.adc8(util.getByte(regs.HL.HiLo()), true)
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x8F - ADC A,A
+				return util.adc8(regs.AF.Hi(), true)
+			},
+			func() (int, int) {
+				// 0x90 - SUB B
+				return util.sub8(regs.BC.Hi())
+			},
+			func() (int, int) {
+				// 0x91 - SUB C
+				return util.sub8(regs.BC.Lo())
+			},
+			func() (int, int) {
+				// 0x92 - SUB D
+				return util.sub8(regs.DE.Hi())
+			},
+			func() (int, int) {
+				// 0x93 - SUB E
+				return util.sub8(regs.DE.Lo())
+			},
+			func() (int, int) {
+				// 0x94 - SUB H
+				return util.sub8(regs.HL.Hi())
+			},
+			func() (int, int) {
+				// 0x95 - SUB L
+				return util.sub8(regs.HL.Lo())
+			},
+			func() (int, int) {
+				// 0x96 - SUB (HL)
+				util.sub8(util.getByte(regs.HL.HiLo()))
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x97 - SUB A
+				return util.sub8(regs.AF.Hi())
+			},
+			func() (int, int) {
+				// 0x98 - SBC A,B
+				return util.sbc8(regs.BC.Hi(), true)
+			},
+			func() (int, int) {
+				// 0x99 - SBC A,C
+				return util.sbc8(regs.BC.Lo(), true)
+			},
+			func() (int, int) {
+				// 0x9A - SBC A,D
+				return util.sbc8(regs.DE.Hi(), true)
+			},
+			func() (int, int) {
+				// 0x9B - SBC A,E
+				return util.sbc8(regs.DE.Lo(), true)
+			},
+			func() (int, int) {
+				// 0x9C - SBC A,H
+				return util.sbc8(regs.HL.Hi(), true)
+			},
+			func() (int, int) {
+				// 0x9D - SBC A,L
+				return util.sbc8(regs.HL.Lo(), true)
+			},
+			func() (int, int) {
+				// 0x9E - SBC A,(HL)
+				util.sbc8(util.getByte(regs.HL.HiLo()), true)
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0x9F - SBC A,A
+				return util.sbc8(regs.AF.Hi(), true)
+			},
+			func() (int, int) {
+				// 0xA0 - AND B
+				return util.and8(regs.BC.Hi())
+			},
+			func() (int, int) {
+				// 0xA1 - AND C
+				return util.and8(regs.BC.Lo())
+			},
+			func() (int, int) {
+				// 0xA2 - AND D
+				return util.and8(regs.DE.Hi())
+			},
+			func() (int, int) {
+				// 0xA3 - AND E
+				return util.and8(regs.DE.Lo())
+			},
+			func() (int, int) {
+				// 0xA4 - AND H
+				return util.and8(regs.HL.Hi())
+			},
+			func() (int, int) {
+				// 0xA5 - AND L
+				return util.and8(regs.HL.Lo())
+			},
+			func() (int, int) {
+				// 0xA6 - AND (HL)
+				util.and8(util.getByte(regs.HL.HiLo()))
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0xA7 - AND A
+				return util.and8(regs.AF.Hi())
+			},
+			func() (int, int) {
+				// 0xA8 - XOR B
+				return util.xor8(regs.BC.Hi())
+			},
+			func() (int, int) {
+				// 0xA9 - XOR C
+				return util.xor8(regs.BC.Lo())
+			},
+			func() (int, int) {
+				// 0xAA - XOR D
+				return util.xor8(regs.DE.Hi())
+			},
+			func() (int, int) {
+				// 0xAB - XOR E
+				return util.xor8(regs.DE.Lo())
+			},
+			func() (int, int) {
+				// 0xAC - XOR H
+				return util.xor8(regs.HL.Hi())
+			},
+			func() (int, int) {
+				// 0xAD - XOR L
+				return util.xor8(regs.HL.Lo())
+			},
+			func() (int, int) {
+				// 0xAE - XOR (HL)
+				util.xor8(util.getByte(regs.HL.HiLo()))
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0xAF - XOR A
+				return util.xor8(regs.AF.Hi())
+			},
+			func() (int, int) {
+				// 0xB0 - OR B
+				return util.or8(regs.BC.Hi())
+			},
+			func() (int, int) {
+				// 0xB1 - OR C
+				return util.or8(regs.BC.Lo())
+			},
+			func() (int, int) {
+				// 0xB2 - OR D
+				return util.or8(regs.DE.Hi())
+			},
+			func() (int, int) {
+				// 0xB3 - OR E
+				return util.or8(regs.DE.Lo())
+			},
+			func() (int, int) {
+				// 0xB4 - OR H
+				return util.or8(regs.HL.Hi())
+			},
+			func() (int, int) {
+				// 0xB5 - OR L
+				return util.or8(regs.HL.Lo())
+			},
+			func() (int, int) {
+				// 0xB6 - OR (HL)
+				util.or8(util.getByte(regs.HL.HiLo()))
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0xB7 - OR A
+				return util.or8(regs.AF.Hi())
+			},
+			func() (int, int) {
+				// 0xB8 - CP B
+				return util.cp8(regs.BC.Hi())
+			},
+			func() (int, int) {
+				// 0xB9 - CP C
+				return util.cp8(regs.BC.Lo())
+			},
+			func() (int, int) {
+				// 0xBA - CP D
+				return util.cp8(regs.DE.Hi())
+			},
+			func() (int, int) {
+				// 0xBB - CP E
+				return util.cp8(regs.DE.Lo())
+			},
+			func() (int, int) {
+				// 0xBC - CP H
+				return util.cp8(regs.HL.Hi())
+			},
+			func() (int, int) {
+				// 0xBD - CP L
+				return util.cp8(regs.HL.Lo())
+			},
+			func() (int, int) {
+				// 0xBE - CP (HL)
+				util.cp8(util.getByte(regs.HL.HiLo()))
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0xBF - CP A
+				return util.cp8(regs.AF.Hi())
+			},
+			func() (int, int) {
+				// 0xC0 - RET NZ
+				return util.retCond(!regs.Z())
+			},
+			func() (int, int) {
+				// 0xC1 - POP BC
+				return util.pop(regs.BC.Set)
+			},
+			func() (int, int) {
+				// 0xC2 - JP NZ,a16
+				return util.jp(!regs.Z())
+			},
+			func() (int, int) {
+				// 0xC3 - JP a16
+				return util.jp(true)
+			},
+			func() (int, int) {
+				// 0xC4 - CALL NZ,a16
+				return util.call(!regs.Z())
+			},
+			func() (int, int) {
+				// 0xC5 - PUSH BC
+				return util.push(regs.BC.HiLo())
+			},
+			func() (int, int) {
+				// 0xC6 - ADD A,d8
+				util.add8(util.getByteAtPC(1))
+				return 2, 8
+			},
+			func() (int, int) {
+				// 0xC7 - RST 00H
+				return util.rst(0x0000)
+			},
+			func() (int, int) {
+				// 0xC8 - RET Z
+				return util.retCond(regs.Z())
+			},
+			func() (int, int) {
+				// 0xC9 - RET
+				return util.ret()
+			},
+			func() (int, int) {
+				// 0xCA - JP Z,a16
+				return util.jp(regs.Z())
+			},
+			func() (int, int) {
+				// 0xCB - PREFIX CB
+				// The CB prefix is handled by the CPU, which runs the
+				// instruction in the CBPrefix set selected by the next byte.
+				// The length and cycles of the prefix are already included
+				// in the ones of the CB-prefixed instructions.
+				return 1, 4
+			},
+			func() (int, int) {
+				// 0xCC - CALL Z,a16
+				return util.call(regs.Z())
+			},
+			func() (int, int) {
+				// 0xCD - CALL a16
+				return util.call(true)
+			},
+			func() (int, int) {
+				// 0xCE - ADC A,d8
+				util.adc8(util.getByteAtPC(1), true)
+				return 2, 8
+			},
+			func() (int, int) {
+				// 0xCF - RST 08H
+				return util.rst(0x0008)
+			},
+			func() (int, int) {
+				// 0xD0 - RET NC
+				return util.retCond(!regs.C())
+			},
+			func() (int, int) {
+				// 0xD1 - POP DE
+				return util.pop(regs.DE.Set)
+			},
+			func() (int, int) {
+				// 0xD2 - JP NC,a16
+				return util.jp(!regs.C())
+			},
+			func() (int, int) {
+				// 0xD3 - Illegal
+				return util.illegal()
+			},
+			func() (int, int) {
+				// 0xD4 - CALL NC,a16
+				return util.call(!regs.C())
+			},
+			func() (int, int) {
+				// 0xD5 - PUSH DE
+				return util.push(regs.DE.HiLo())
+			},
+			func() (int, int) {
+				// 0xD6 - SUB d8
+				util.sub8(util.getByteAtPC(1))
+				return 2, 8
+			},
+			func() (int, int) {
+				// 0xD7 - RST 10H
+				return util.rst(0x0010)
+			},
+			func() (int, int) {
+				// 0xD8 - RET C
+				return util.retCond(regs.C())
+			},
+			func() (int, int) {
+				// 0xD9 - RETI
+				stateMgr.SetIME(true)
+				return util.ret()
+			},
+			func() (int, int) {
+				// 0xDA - JP C,a16
+				return util.jp(regs.C())
+			},
+			func() (int, int) {
+				// 0xDB - Illegal
+				return util.illegal()
+			},
+			func() (int, int) {
+				// 0xDC - CALL C,a16
+				return util.call(regs.C())
+			},
+			func() (int, int) {
+				// 0xDD - Illegal
+				return util.illegal()
+			},
+			func() (int, int) {
+				// 0xDE - SBC A,d8
+				util.sbc8(util.getByteAtPC(1), true)
+				return 2, 8
+			},
+			func() (int, int) {
+				// 0xDF - RST 18H
+				return util.rst(0x0018)
+			},
+			func() (int, int) {
+				// 0xE0 - LDH (a8),A
+				util.setByte(0xFF00+uint16(util.getByteAtPC(1)), regs.AF.Hi())
+				return 2, 12
+			},
+			func() (int, int) {
+				// 0xE1 - POP HL
+				return util.pop(regs.HL.Set)
+			},
+			func() (int, int) {
+				// 0xE2 - LD (C),A
+				util.setByte(0xFF00+uint16(regs.BC.Lo()), regs.AF.Hi())
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0xE3 - Illegal
+				return util.illegal()
+			},
+			func() (int, int) {
+				// 0xE4 - Illegal
+				return util.illegal()
+			},
+			func() (int, int) {
+				// 0xE5 - PUSH HL
+				return util.push(regs.HL.HiLo())
+			},
+			func() (int, int) {
+				// 0xE6 - AND d8
+				util.and8(util.getByteAtPC(1))
+				return 2, 8
+			},
+			func() (int, int) {
+				// 0xE7 - RST 20H
+				return util.rst(0x0020)
+			},
+			func() (int, int) {
+				// 0xE8 - ADD SP,r8
+				regs.SP.Set(util.addSPr8())
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xE9 - JP (HL)
+				util.jump(regs.HL.HiLo(), 1)
+				return 1, 4
+			},
+			func() (int, int) {
+				// 0xEA - LD (a16),A
+				util.setByte(util.getD16AtPC(), regs.AF.Hi())
+				return 3, 16
+			},
+			func() (int, int) {
+				// 0xEB - Illegal
+				return util.illegal()
+			},
+			func() (int, int) {
+				// 0xEC - Illegal
+				return util.illegal()
+			},
+			func() (int, int) {
+				// 0xED - Illegal
+				return util.illegal()
+			},
+			func() (int, int) {
+				// 0xEE - XOR d8
+				util.xor8(util.getByteAtPC(1))
+				return 2, 8
+			},
+			func() (int, int) {
+				// 0xEF - RST 28H
+				return util.rst(0x0028)
+			},
+			func() (int, int) {
+				// 0xF0 - LDH A,(a8)
+				regs.AF.SetHi(util.getByte(0xFF00 + uint16(util.getByteAtPC(1))))
+				return 2, 12
+			},
+			func() (int, int) {
+				// 0xF1 - POP AF
+				// The lower 4 bits of F are always 0, which is
+				// guaranteed by the mask of the AF register.
+				return util.pop(regs.AF.Set)
+			},
+			func() (int, int) {
+				// 0xF2 - LD A,(C)
+				regs.AF.SetHi(util.getByte(0xFF00 + uint16(regs.BC.Lo())))
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0xF3 - DI
+				stateMgr.SetIME(false)
+				return 1, 4
+			},
+			func() (int, int) {
+				// 0xF4 - Illegal
+				return util.illegal()
+			},
+			func() (int, int) {
+				// 0xF5 - PUSH AF
+				return util.push(regs.AF.HiLo())
+			},
+			func() (int, int) {
+				// 0xF6 - OR d8
+				util.or8(util.getByteAtPC(1))
+				return 2, 8
+			},
+			func() (int, int) {
+				// 0xF7 - RST 30H
+				return util.rst(0x0030)
+			},
+			func() (int, int) {
+				// 0xF8 - LD HL,SP+r8
+				regs.HL.Set(util.addSPr8())
+				return 2, 12
+			},
+			func() (int, int) {
+				// 0xF9 - LD SP,HL
+				regs.SP.Set(regs.HL.HiLo())
+				return 1, 8
+			},
+			func() (int, int) {
+				// 0xFA - LD A,(a16)
+				regs.AF.SetHi(util.getByte(util.getD16AtPC()))
+				return 3, 16
+			},
+			func() (int, int) {
+				// 0xFB - EI
+				stateMgr.ScheduleIME()
+				return 1, 4
+			},
+			func() (int, int) {
+				// 0xFC - Illegal
+				return util.illegal()
+			},
+			func() (int, int) {
+				// 0xFD - Illegal
+				return util.illegal()
+			},
+			func() (int, int) {
+				// 0xFE - CP d8
+				util.cp8(util.getByteAtPC(1))
+				return 2, 8
+			},
+			func() (int, int) {
+				// 0xFF - RST 38H
+				return util.rst(0x0038)
+			},
+		},
+		CBPrefix: []Instr{
+			func() (int, int) {
+				// 0xCB 0x00 - RLC B
+				return util.rlc(regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x01 - RLC C
+				return util.rlc(regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x02 - RLC D
+				return util.rlc(regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x03 - RLC E
+				return util.rlc(regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x04 - RLC H
+				return util.rlc(regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x05 - RLC L
+				return util.rlc(regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x06 - RLC (HL)
+				addr := regs.HL.HiLo()
+				util.rlc(util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0x07 - RLC A
+				return util.rlc(regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x08 - RRC B
+				return util.rrc(regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x09 - RRC C
+				return util.rrc(regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x0A - RRC D
+				return util.rrc(regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x0B - RRC E
+				return util.rrc(regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x0C - RRC H
+				return util.rrc(regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x0D - RRC L
+				return util.rrc(regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x0E - RRC (HL)
+				addr := regs.HL.HiLo()
+				util.rrc(util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0x0F - RRC A
+				return util.rrc(regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x10 - RL B
+				return util.rl(regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x11 - RL C
+				return util.rl(regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x12 - RL D
+				return util.rl(regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x13 - RL E
+				return util.rl(regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x14 - RL H
+				return util.rl(regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x15 - RL L
+				return util.rl(regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x16 - RL (HL)
+				addr := regs.HL.HiLo()
+				util.rl(util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0x17 - RL A
+				return util.rl(regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x18 - RR B
+				return util.rr(regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x19 - RR C
+				return util.rr(regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x1A - RR D
+				return util.rr(regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x1B - RR E
+				return util.rr(regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x1C - RR H
+				return util.rr(regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x1D - RR L
+				return util.rr(regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x1E - RR (HL)
+				addr := regs.HL.HiLo()
+				util.rr(util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0x1F - RR A
+				return util.rr(regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x20 - SLA B
+				return util.sla(regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x21 - SLA C
+				return util.sla(regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x22 - SLA D
+				return util.sla(regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x23 - SLA E
+				return util.sla(regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x24 - SLA H
+				return util.sla(regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x25 - SLA L
+				return util.sla(regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x26 - SLA (HL)
+				addr := regs.HL.HiLo()
+				util.sla(util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0x27 - SLA A
+				return util.sla(regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x28 - SRA B
+				return util.sra(regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x29 - SRA C
+				return util.sra(regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x2A - SRA D
+				return util.sra(regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x2B - SRA E
+				return util.sra(regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x2C - SRA H
+				return util.sra(regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x2D - SRA L
+				return util.sra(regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x2E - SRA (HL)
+				addr := regs.HL.HiLo()
+				util.sra(util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0x2F - SRA A
+				return util.sra(regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x30 - SWAP B
+				return util.swap(regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x31 - SWAP C
+				return util.swap(regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x32 - SWAP D
+				return util.swap(regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x33 - SWAP E
+				return util.swap(regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x34 - SWAP H
+				return util.swap(regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x35 - SWAP L
+				return util.swap(regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x36 - SWAP (HL)
+				addr := regs.HL.HiLo()
+				util.swap(util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0x37 - SWAP A
+				return util.swap(regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x38 - SRL B
+				return util.srl(regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x39 - SRL C
+				return util.srl(regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x3A - SRL D
+				return util.srl(regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x3B - SRL E
+				return util.srl(regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x3C - SRL H
+				return util.srl(regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x3D - SRL L
+				return util.srl(regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x3E - SRL (HL)
+				addr := regs.HL.HiLo()
+				util.srl(util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0x3F - SRL A
+				return util.srl(regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x40 - BIT 0,B
+				return util.bit(0, regs.BC.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x41 - BIT 0,C
+				return util.bit(0, regs.BC.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x42 - BIT 0,D
+				return util.bit(0, regs.DE.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x43 - BIT 0,E
+				return util.bit(0, regs.DE.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x44 - BIT 0,H
+				return util.bit(0, regs.HL.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x45 - BIT 0,L
+				return util.bit(0, regs.HL.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x46 - BIT 0,(HL)
+				util.bit(0, util.getByte(regs.HL.HiLo()))
+				return 2, 12
+			},
+			func() (int, int) {
+				// 0xCB 0x47 - BIT 0,A
+				return util.bit(0, regs.AF.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x48 - BIT 1,B
+				return util.bit(1, regs.BC.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x49 - BIT 1,C
+				return util.bit(1, regs.BC.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x4A - BIT 1,D
+				return util.bit(1, regs.DE.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x4B - BIT 1,E
+				return util.bit(1, regs.DE.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x4C - BIT 1,H
+				return util.bit(1, regs.HL.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x4D - BIT 1,L
+				return util.bit(1, regs.HL.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x4E - BIT 1,(HL)
+				util.bit(1, util.getByte(regs.HL.HiLo()))
+				return 2, 12
+			},
+			func() (int, int) {
+				// 0xCB 0x4F - BIT 1,A
+				return util.bit(1, regs.AF.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x50 - BIT 2,B
+				return util.bit(2, regs.BC.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x51 - BIT 2,C
+				return util.bit(2, regs.BC.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x52 - BIT 2,D
+				return util.bit(2, regs.DE.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x53 - BIT 2,E
+				return util.bit(2, regs.DE.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x54 - BIT 2,H
+				return util.bit(2, regs.HL.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x55 - BIT 2,L
+				return util.bit(2, regs.HL.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x56 - BIT 2,(HL)
+				util.bit(2, util.getByte(regs.HL.HiLo()))
+				return 2, 12
+			},
+			func() (int, int) {
+				// 0xCB 0x57 - BIT 2,A
+				return util.bit(2, regs.AF.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x58 - BIT 3,B
+				return util.bit(3, regs.BC.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x59 - BIT 3,C
+				return util.bit(3, regs.BC.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x5A - BIT 3,D
+				return util.bit(3, regs.DE.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x5B - BIT 3,E
+				return util.bit(3, regs.DE.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x5C - BIT 3,H
+				return util.bit(3, regs.HL.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x5D - BIT 3,L
+				return util.bit(3, regs.HL.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x5E - BIT 3,(HL)
+				util.bit(3, util.getByte(regs.HL.HiLo()))
+				return 2, 12
+			},
+			func() (int, int) {
+				// 0xCB 0x5F - BIT 3,A
+				return util.bit(3, regs.AF.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x60 - BIT 4,B
+				return util.bit(4, regs.BC.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x61 - BIT 4,C
+				return util.bit(4, regs.BC.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x62 - BIT 4,D
+				return util.bit(4, regs.DE.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x63 - BIT 4,E
+				return util.bit(4, regs.DE.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x64 - BIT 4,H
+				return util.bit(4, regs.HL.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x65 - BIT 4,L
+				return util.bit(4, regs.HL.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x66 - BIT 4,(HL)
+				util.bit(4, util.getByte(regs.HL.HiLo()))
+				return 2, 12
+			},
+			func() (int, int) {
+				// 0xCB 0x67 - BIT 4,A
+				return util.bit(4, regs.AF.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x68 - BIT 5,B
+				return util.bit(5, regs.BC.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x69 - BIT 5,C
+				return util.bit(5, regs.BC.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x6A - BIT 5,D
+				return util.bit(5, regs.DE.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x6B - BIT 5,E
+				return util.bit(5, regs.DE.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x6C - BIT 5,H
+				return util.bit(5, regs.HL.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x6D - BIT 5,L
+				return util.bit(5, regs.HL.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x6E - BIT 5,(HL)
+				util.bit(5, util.getByte(regs.HL.HiLo()))
+				return 2, 12
+			},
+			func() (int, int) {
+				// 0xCB 0x6F - BIT 5,A
+				return util.bit(5, regs.AF.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x70 - BIT 6,B
+				return util.bit(6, regs.BC.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x71 - BIT 6,C
+				return util.bit(6, regs.BC.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x72 - BIT 6,D
+				return util.bit(6, regs.DE.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x73 - BIT 6,E
+				return util.bit(6, regs.DE.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x74 - BIT 6,H
+				return util.bit(6, regs.HL.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x75 - BIT 6,L
+				return util.bit(6, regs.HL.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x76 - BIT 6,(HL)
+				util.bit(6, util.getByte(regs.HL.HiLo()))
+				return 2, 12
+			},
+			func() (int, int) {
+				// 0xCB 0x77 - BIT 6,A
+				return util.bit(6, regs.AF.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x78 - BIT 7,B
+				return util.bit(7, regs.BC.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x79 - BIT 7,C
+				return util.bit(7, regs.BC.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x7A - BIT 7,D
+				return util.bit(7, regs.DE.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x7B - BIT 7,E
+				return util.bit(7, regs.DE.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x7C - BIT 7,H
+				return util.bit(7, regs.HL.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x7D - BIT 7,L
+				return util.bit(7, regs.HL.Lo())
+			},
+			func() (int, int) {
+				// 0xCB 0x7E - BIT 7,(HL)
+				util.bit(7, util.getByte(regs.HL.HiLo()))
+				return 2, 12
+			},
+			func() (int, int) {
+				// 0xCB 0x7F - BIT 7,A
+				return util.bit(7, regs.AF.Hi())
+			},
+			func() (int, int) {
+				// 0xCB 0x80 - RES 0,B
+				return util.resBit(0, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x81 - RES 0,C
+				return util.resBit(0, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x82 - RES 0,D
+				return util.resBit(0, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x83 - RES 0,E
+				return util.resBit(0, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x84 - RES 0,H
+				return util.resBit(0, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x85 - RES 0,L
+				return util.resBit(0, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x86 - RES 0,(HL)
+				addr := regs.HL.HiLo()
+				util.resBit(0, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0x87 - RES 0,A
+				return util.resBit(0, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x88 - RES 1,B
+				return util.resBit(1, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x89 - RES 1,C
+				return util.resBit(1, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x8A - RES 1,D
+				return util.resBit(1, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x8B - RES 1,E
+				return util.resBit(1, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x8C - RES 1,H
+				return util.resBit(1, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x8D - RES 1,L
+				return util.resBit(1, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x8E - RES 1,(HL)
+				addr := regs.HL.HiLo()
+				util.resBit(1, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0x8F - RES 1,A
+				return util.resBit(1, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x90 - RES 2,B
+				return util.resBit(2, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x91 - RES 2,C
+				return util.resBit(2, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x92 - RES 2,D
+				return util.resBit(2, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x93 - RES 2,E
+				return util.resBit(2, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x94 - RES 2,H
+				return util.resBit(2, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x95 - RES 2,L
+				return util.resBit(2, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x96 - RES 2,(HL)
+				addr := regs.HL.HiLo()
+				util.resBit(2, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0x97 - RES 2,A
+				return util.resBit(2, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x98 - RES 3,B
+				return util.resBit(3, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x99 - RES 3,C
+				return util.resBit(3, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x9A - RES 3,D
+				return util.resBit(3, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x9B - RES 3,E
+				return util.resBit(3, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x9C - RES 3,H
+				return util.resBit(3, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0x9D - RES 3,L
+				return util.resBit(3, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0x9E - RES 3,(HL)
+				addr := regs.HL.HiLo()
+				util.resBit(3, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0x9F - RES 3,A
+				return util.resBit(3, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xA0 - RES 4,B
+				return util.resBit(4, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xA1 - RES 4,C
+				return util.resBit(4, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xA2 - RES 4,D
+				return util.resBit(4, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xA3 - RES 4,E
+				return util.resBit(4, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xA4 - RES 4,H
+				return util.resBit(4, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xA5 - RES 4,L
+				return util.resBit(4, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xA6 - RES 4,(HL)
+				addr := regs.HL.HiLo()
+				util.resBit(4, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0xA7 - RES 4,A
+				return util.resBit(4, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xA8 - RES 5,B
+				return util.resBit(5, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xA9 - RES 5,C
+				return util.resBit(5, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xAA - RES 5,D
+				return util.resBit(5, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xAB - RES 5,E
+				return util.resBit(5, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xAC - RES 5,H
+				return util.resBit(5, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xAD - RES 5,L
+				return util.resBit(5, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xAE - RES 5,(HL)
+				addr := regs.HL.HiLo()
+				util.resBit(5, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0xAF - RES 5,A
+				return util.resBit(5, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xB0 - RES 6,B
+				return util.resBit(6, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xB1 - RES 6,C
+				return util.resBit(6, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xB2 - RES 6,D
+				return util.resBit(6, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xB3 - RES 6,E
+				return util.resBit(6, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xB4 - RES 6,H
+				return util.resBit(6, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xB5 - RES 6,L
+				return util.resBit(6, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xB6 - RES 6,(HL)
+				addr := regs.HL.HiLo()
+				util.resBit(6, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0xB7 - RES 6,A
+				return util.resBit(6, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xB8 - RES 7,B
+				return util.resBit(7, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xB9 - RES 7,C
+				return util.resBit(7, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xBA - RES 7,D
+				return util.resBit(7, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xBB - RES 7,E
+				return util.resBit(7, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xBC - RES 7,H
+				return util.resBit(7, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xBD - RES 7,L
+				return util.resBit(7, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xBE - RES 7,(HL)
+				addr := regs.HL.HiLo()
+				util.resBit(7, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0xBF - RES 7,A
+				return util.resBit(7, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xC0 - SET 0,B
+				return util.setBit(0, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xC1 - SET 0,C
+				return util.setBit(0, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xC2 - SET 0,D
+				return util.setBit(0, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xC3 - SET 0,E
+				return util.setBit(0, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xC4 - SET 0,H
+				return util.setBit(0, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xC5 - SET 0,L
+				return util.setBit(0, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xC6 - SET 0,(HL)
+				addr := regs.HL.HiLo()
+				util.setBit(0, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0xC7 - SET 0,A
+				return util.setBit(0, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xC8 - SET 1,B
+				return util.setBit(1, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xC9 - SET 1,C
+				return util.setBit(1, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xCA - SET 1,D
+				return util.setBit(1, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xCB - SET 1,E
+				return util.setBit(1, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xCC - SET 1,H
+				return util.setBit(1, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xCD - SET 1,L
+				return util.setBit(1, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xCE - SET 1,(HL)
+				addr := regs.HL.HiLo()
+				util.setBit(1, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0xCF - SET 1,A
+				return util.setBit(1, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xD0 - SET 2,B
+				return util.setBit(2, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xD1 - SET 2,C
+				return util.setBit(2, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xD2 - SET 2,D
+				return util.setBit(2, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xD3 - SET 2,E
+				return util.setBit(2, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xD4 - SET 2,H
+				return util.setBit(2, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xD5 - SET 2,L
+				return util.setBit(2, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xD6 - SET 2,(HL)
+				addr := regs.HL.HiLo()
+				util.setBit(2, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0xD7 - SET 2,A
+				return util.setBit(2, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xD8 - SET 3,B
+				return util.setBit(3, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xD9 - SET 3,C
+				return util.setBit(3, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xDA - SET 3,D
+				return util.setBit(3, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xDB - SET 3,E
+				return util.setBit(3, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xDC - SET 3,H
+				return util.setBit(3, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xDD - SET 3,L
+				return util.setBit(3, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xDE - SET 3,(HL)
+				addr := regs.HL.HiLo()
+				util.setBit(3, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0xDF - SET 3,A
+				return util.setBit(3, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xE0 - SET 4,B
+				return util.setBit(4, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xE1 - SET 4,C
+				return util.setBit(4, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xE2 - SET 4,D
+				return util.setBit(4, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xE3 - SET 4,E
+				return util.setBit(4, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xE4 - SET 4,H
+				return util.setBit(4, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xE5 - SET 4,L
+				return util.setBit(4, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xE6 - SET 4,(HL)
+				addr := regs.HL.HiLo()
+				util.setBit(4, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0xE7 - SET 4,A
+				return util.setBit(4, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xE8 - SET 5,B
+				return util.setBit(5, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xE9 - SET 5,C
+				return util.setBit(5, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xEA - SET 5,D
+				return util.setBit(5, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xEB - SET 5,E
+				return util.setBit(5, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xEC - SET 5,H
+				return util.setBit(5, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xED - SET 5,L
+				return util.setBit(5, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xEE - SET 5,(HL)
+				addr := regs.HL.HiLo()
+				util.setBit(5, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0xEF - SET 5,A
+				return util.setBit(5, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xF0 - SET 6,B
+				return util.setBit(6, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xF1 - SET 6,C
+				return util.setBit(6, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xF2 - SET 6,D
+				return util.setBit(6, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xF3 - SET 6,E
+				return util.setBit(6, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xF4 - SET 6,H
+				return util.setBit(6, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xF5 - SET 6,L
+				return util.setBit(6, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xF6 - SET 6,(HL)
+				addr := regs.HL.HiLo()
+				util.setBit(6, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0xF7 - SET 6,A
+				return util.setBit(6, regs.AF.Hi(), regs.AF.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xF8 - SET 7,B
+				return util.setBit(7, regs.BC.Hi(), regs.BC.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xF9 - SET 7,C
+				return util.setBit(7, regs.BC.Lo(), regs.BC.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xFA - SET 7,D
+				return util.setBit(7, regs.DE.Hi(), regs.DE.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xFB - SET 7,E
+				return util.setBit(7, regs.DE.Lo(), regs.DE.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xFC - SET 7,H
+				return util.setBit(7, regs.HL.Hi(), regs.HL.SetHi)
+			},
+			func() (int, int) {
+				// 0xCB 0xFD - SET 7,L
+				return util.setBit(7, regs.HL.Lo(), regs.HL.SetLo)
+			},
+			func() (int, int) {
+				// 0xCB 0xFE - SET 7,(HL)
+				addr := regs.HL.HiLo()
+				util.setBit(7, util.getByte(addr), func(v byte) { util.setByte(addr, v) })
+				return 2, 16
+			},
+			func() (int, int) {
+				// 0xCB 0xFF - SET 7,A
+				return util.setBit(7, regs.AF.Hi(), regs.AF.SetHi)
+			},
+		},
+	}
+}
+
+// takeErr returns the error raised by the last instruction
+// that has been run, if any, and clears it.
+func (s *InstrSet) takeErr() *errors.Error {
+	err := s.util.err
+	s.util.err = nil
+	return err
 }
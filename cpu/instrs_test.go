@@ -5,6 +5,7 @@ import (
 
 	"github.com/lucactt/gameboy/mem"
 	"github.com/lucactt/gameboy/util/assert"
+	"github.com/lucactt/gameboy/util/errors"
 )
 
 func Test_NewInstrSet(t *testing.T) {
@@ -137,7 +138,7 @@ func Test_NewInstrSet(t *testing.T) {
 				set.NoPrefix[0x07]()
 
 				assert.Equal(t, regs.AF.Hi(), byte(0x00))
-				assert.Equal(t, regs.Z(), true)
+				assert.Equal(t, regs.Z(), false)
 				assert.Equal(t, regs.N(), false)
 				assert.Equal(t, regs.H(), false)
 				assert.Equal(t, regs.C(), false)
@@ -272,7 +273,7 @@ func Test_NewInstrSet(t *testing.T) {
 				set.NoPrefix[0x0F]()
 
 				assert.Equal(t, regs.AF.Hi(), byte(0x00))
-				assert.Equal(t, regs.Z(), true)
+				assert.Equal(t, regs.Z(), false)
 				assert.Equal(t, regs.N(), false)
 				assert.Equal(t, regs.H(), false)
 				assert.Equal(t, regs.C(), false)
@@ -407,7 +408,7 @@ func Test_NewInstrSet(t *testing.T) {
 				set.NoPrefix[0x07]()
 
 				assert.Equal(t, regs.AF.Hi(), byte(0x00))
-				assert.Equal(t, regs.Z(), true)
+				assert.Equal(t, regs.Z(), false)
 				assert.Equal(t, regs.N(), false)
 				assert.Equal(t, regs.H(), false)
 				assert.Equal(t, regs.C(), false)
@@ -494,5 +495,639 @@ func Test_NewInstrSet(t *testing.T) {
 			assert.Equal(t, len, 2)
 			assert.Equal(t, cycles, 8)
 		})
+
+		t.Run("JR NZ,r8", func(t *testing.T) {
+			t.Run("taken, negative offset", func(t *testing.T) {
+				regs := NewRegs()
+				ram := mem.NewRAM(regs.PC.HiLo() + 2)
+				stateMgr := NewStateMgr()
+				set := NewInstrSet(regs, ram, stateMgr)
+
+				regs.SetZ(false)
+				ram.SetByte(regs.PC.HiLo()+1, 0xFE) // -2
+
+				len, cycles := set.NoPrefix[0x20]()
+
+				// The CPU will add the length to PC, jumping back to 0x0100.
+				assert.Equal(t, regs.PC.HiLo()+uint16(len), uint16(0x0100))
+				assert.Equal(t, len, 2)
+				assert.Equal(t, cycles, 12)
+			})
+
+			t.Run("not taken", func(t *testing.T) {
+				regs := NewRegs()
+				ram := mem.NewRAM(regs.PC.HiLo() + 2)
+				stateMgr := NewStateMgr()
+				set := NewInstrSet(regs, ram, stateMgr)
+
+				regs.SetZ(true)
+				ram.SetByte(regs.PC.HiLo()+1, 0x10)
+
+				len, cycles := set.NoPrefix[0x20]()
+
+				assert.Equal(t, regs.PC.HiLo(), uint16(0x0100))
+				assert.Equal(t, len, 2)
+				assert.Equal(t, cycles, 8)
+			})
+		})
+
+		t.Run("LD SP,d16", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(regs.PC.HiLo() + 3)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			ram.SetByte(regs.PC.HiLo()+1, 0x01)
+			ram.SetByte(regs.PC.HiLo()+2, 0x11)
+
+			len, cycles := set.NoPrefix[0x31]()
+
+			assert.Equal(t, regs.SP.HiLo(), uint16(0x1101))
+			assert.Equal(t, len, 3)
+			assert.Equal(t, cycles, 12)
+		})
+
+		t.Run("LD (HL-),A", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(2)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.HL.Set(0x0001)
+			regs.AF.SetHi(0x11)
+
+			len, cycles := set.NoPrefix[0x32]()
+
+			got, _ := ram.GetByte(0x0001)
+			assert.Equal(t, got, byte(0x11))
+			assert.Equal(t, regs.HL.HiLo(), uint16(0x0000))
+			assert.Equal(t, len, 1)
+			assert.Equal(t, cycles, 8)
+		})
+
+		t.Run("INC (HL)", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(1)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.HL.Set(0x0000)
+			ram.SetByte(0x0000, 0x0F)
+
+			len, cycles := set.NoPrefix[0x34]()
+
+			got, _ := ram.GetByte(0x0000)
+			assert.Equal(t, got, byte(0x10))
+			assert.Equal(t, regs.H(), true)
+			assert.Equal(t, len, 1)
+			assert.Equal(t, cycles, 12)
+		})
+
+		t.Run("SCF", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(0)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.SetC(false)
+
+			set.NoPrefix[0x37]()
+
+			assert.Equal(t, regs.C(), true)
+			assert.Equal(t, regs.N(), false)
+			assert.Equal(t, regs.H(), false)
+		})
+
+		t.Run("CCF", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(0)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.SetC(true)
+
+			set.NoPrefix[0x3F]()
+
+			assert.Equal(t, regs.C(), false)
+		})
+
+		t.Run("LD B,C", func(t *testing.T) {
+			testLd8(t, 0x41,
+				func(regs *Regs) byte { return regs.BC.Hi() },
+				func(regs *Regs, v byte) { regs.BC.SetLo(v) })
+		})
+
+		t.Run("LD D,A", func(t *testing.T) {
+			testLd8(t, 0x57,
+				func(regs *Regs) byte { return regs.DE.Hi() },
+				func(regs *Regs, v byte) { regs.AF.SetHi(v) })
+		})
+
+		t.Run("LD A,L", func(t *testing.T) {
+			testLd8(t, 0x7D,
+				func(regs *Regs) byte { return regs.AF.Hi() },
+				func(regs *Regs, v byte) { regs.HL.SetLo(v) })
+		})
+
+		t.Run("LD (HL),E", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(1)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.HL.Set(0x0000)
+			regs.DE.SetLo(0x11)
+
+			len, cycles := set.NoPrefix[0x73]()
+
+			got, _ := ram.GetByte(0x0000)
+			assert.Equal(t, got, byte(0x11))
+			assert.Equal(t, len, 1)
+			assert.Equal(t, cycles, 8)
+		})
+
+		t.Run("HALT", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(0)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			len, cycles := set.NoPrefix[0x76]()
+
+			assert.Equal(t, stateMgr.current, Halted)
+			assert.Equal(t, len, 1)
+			assert.Equal(t, cycles, 4)
+		})
+
+		t.Run("ALU", func(t *testing.T) {
+			setB := func(regs *Regs, v byte) { regs.BC.SetHi(v) }
+
+			tests := []struct {
+				name     string
+				opcode   byte
+				a, value byte
+				carry    bool
+				want     byte
+				flags    [4]bool // Z, N, H, C
+			}{
+				{"ADD A,B", 0x80, 0x0F, 0x01, false, 0x10, [4]bool{false, false, true, false}},
+				{"ADD A,B overflow", 0x80, 0xFF, 0x01, false, 0x00, [4]bool{true, false, true, true}},
+				{"ADC A,B", 0x88, 0x0E, 0x01, true, 0x10, [4]bool{false, false, true, false}},
+				{"SUB B", 0x90, 0x10, 0x01, false, 0x0F, [4]bool{false, true, true, false}},
+				{"SUB B underflow", 0x90, 0x00, 0x01, false, 0xFF, [4]bool{false, true, true, true}},
+				{"SBC A,B", 0x98, 0x02, 0x01, true, 0x00, [4]bool{true, true, false, false}},
+				{"AND B", 0xA0, 0xF0, 0x0F, false, 0x00, [4]bool{true, false, true, false}},
+				{"XOR B", 0xA8, 0xFF, 0x0F, false, 0xF0, [4]bool{false, false, false, false}},
+				{"OR B", 0xB0, 0xF0, 0x0F, false, 0xFF, [4]bool{false, false, false, false}},
+				{"CP B", 0xB8, 0x11, 0x11, false, 0x11, [4]bool{true, true, false, false}},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					testALU8(t, tt.opcode, tt.a, tt.value, func(regs *Regs, v byte) {
+						setB(regs, v)
+						regs.SetC(tt.carry)
+					}, tt.want, tt.flags)
+				})
+			}
+		})
+
+		t.Run("ADD A,(HL)", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(1)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.HL.Set(0x0000)
+			regs.AF.SetHi(0x01)
+			ram.SetByte(0x0000, 0x01)
+
+			len, cycles := set.NoPrefix[0x86]()
+
+			assert.Equal(t, regs.AF.Hi(), byte(0x02))
+			assert.Equal(t, len, 1)
+			assert.Equal(t, cycles, 8)
+		})
+
+		t.Run("CP d8", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(regs.PC.HiLo() + 2)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.AF.SetHi(0x01)
+			ram.SetByte(regs.PC.HiLo()+1, 0x02)
+
+			len, cycles := set.NoPrefix[0xFE]()
+
+			assert.Equal(t, regs.AF.Hi(), byte(0x01))
+			assert.Equal(t, regs.Z(), false)
+			assert.Equal(t, regs.C(), true)
+			assert.Equal(t, len, 2)
+			assert.Equal(t, cycles, 8)
+		})
+
+		t.Run("PUSH BC and POP DE", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(0xFFFF)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.BC.Set(0x1122)
+
+			len, cycles := set.NoPrefix[0xC5]()
+			assert.Equal(t, regs.SP.HiLo(), uint16(0xFFFC))
+			assert.Equal(t, len, 1)
+			assert.Equal(t, cycles, 16)
+
+			len, cycles = set.NoPrefix[0xD1]()
+			assert.Equal(t, regs.DE.HiLo(), uint16(0x1122))
+			assert.Equal(t, regs.SP.HiLo(), uint16(0xFFFE))
+			assert.Equal(t, len, 1)
+			assert.Equal(t, cycles, 12)
+		})
+
+		t.Run("POP AF", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(0xFFFF)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.SP.Set(0xFFFC)
+			ram.SetByte(0xFFFC, 0xFF)
+			ram.SetByte(0xFFFD, 0x11)
+
+			set.NoPrefix[0xF1]()
+
+			assert.Equal(t, regs.AF.HiLo(), uint16(0x11F0))
+		})
+
+		t.Run("POP BC from the top of the memory", func(t *testing.T) {
+			regs := NewRegs()
+			mmu := &mem.MMU{}
+			mmu.AddMem(0x0000, mem.NewRAM(0xFFFF))
+			mmu.AddMem(0xFFFF, mem.NewRAM(0x0001))
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, mmu, stateMgr)
+
+			regs.SP.Set(0xFFFE)
+			mmu.SetByte(0xFFFE, 0x22)
+			mmu.SetByte(0xFFFF, 0x11)
+
+			set.NoPrefix[0xC1]()
+
+			assert.Equal(t, regs.BC.HiLo(), uint16(0x1122))
+			assert.Equal(t, regs.SP.HiLo(), uint16(0x0000))
+		})
+
+		t.Run("JP a16", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(regs.PC.HiLo() + 3)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			ram.SetByte(regs.PC.HiLo()+1, 0x50)
+			ram.SetByte(regs.PC.HiLo()+2, 0x01)
+
+			len, cycles := set.NoPrefix[0xC3]()
+
+			assert.Equal(t, regs.PC.HiLo()+uint16(len), uint16(0x0150))
+			assert.Equal(t, len, 3)
+			assert.Equal(t, cycles, 16)
+		})
+
+		t.Run("JP Z,a16 not taken", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(regs.PC.HiLo() + 3)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.SetZ(false)
+
+			len, cycles := set.NoPrefix[0xCA]()
+
+			assert.Equal(t, regs.PC.HiLo(), uint16(0x0100))
+			assert.Equal(t, len, 3)
+			assert.Equal(t, cycles, 12)
+		})
+
+		t.Run("CALL a16 and RET", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(0xFFFF)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			ram.SetByte(regs.PC.HiLo()+1, 0x00)
+			ram.SetByte(regs.PC.HiLo()+2, 0x20)
+
+			len, cycles := set.NoPrefix[0xCD]()
+			regs.PC.Set(regs.PC.HiLo() + uint16(len))
+
+			assert.Equal(t, regs.PC.HiLo(), uint16(0x2000))
+			assert.Equal(t, regs.SP.HiLo(), uint16(0xFFFC))
+			assert.Equal(t, cycles, 24)
+
+			len, cycles = set.NoPrefix[0xC9]()
+			regs.PC.Set(regs.PC.HiLo() + uint16(len))
+
+			assert.Equal(t, regs.PC.HiLo(), uint16(0x0103))
+			assert.Equal(t, regs.SP.HiLo(), uint16(0xFFFE))
+			assert.Equal(t, cycles, 16)
+		})
+
+		t.Run("RET NC", func(t *testing.T) {
+			t.Run("taken", func(t *testing.T) {
+				regs := NewRegs()
+				ram := mem.NewRAM(0xFFFF)
+				stateMgr := NewStateMgr()
+				set := NewInstrSet(regs, ram, stateMgr)
+
+				regs.SetC(false)
+				regs.SP.Set(0xFFFC)
+				ram.SetByte(0xFFFC, 0x00)
+				ram.SetByte(0xFFFD, 0x20)
+
+				len, cycles := set.NoPrefix[0xD0]()
+
+				assert.Equal(t, regs.PC.HiLo()+uint16(len), uint16(0x2000))
+				assert.Equal(t, cycles, 20)
+			})
+
+			t.Run("not taken", func(t *testing.T) {
+				regs := NewRegs()
+				ram := mem.NewRAM(0xFFFF)
+				stateMgr := NewStateMgr()
+				set := NewInstrSet(regs, ram, stateMgr)
+
+				regs.SetC(true)
+
+				_, cycles := set.NoPrefix[0xD0]()
+
+				assert.Equal(t, regs.SP.HiLo(), uint16(0xFFFE))
+				assert.Equal(t, cycles, 8)
+			})
+		})
+
+		t.Run("RST 38H", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(0xFFFF)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			len, cycles := set.NoPrefix[0xFF]()
+
+			lo, _ := ram.GetByte(0xFFFC)
+			hi, _ := ram.GetByte(0xFFFD)
+			assert.Equal(t, regs.PC.HiLo()+uint16(len), uint16(0x0038))
+			assert.Equal(t, lo, byte(0x01))
+			assert.Equal(t, hi, byte(0x01))
+			assert.Equal(t, cycles, 16)
+		})
+
+		t.Run("LDH (a8),A", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(0xFFFF)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.AF.SetHi(0x11)
+			ram.SetByte(regs.PC.HiLo()+1, 0x80)
+
+			len, cycles := set.NoPrefix[0xE0]()
+
+			got, _ := ram.GetByte(0xFF80)
+			assert.Equal(t, got, byte(0x11))
+			assert.Equal(t, len, 2)
+			assert.Equal(t, cycles, 12)
+		})
+
+		t.Run("LD A,(C)", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(0xFFFF)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.BC.SetLo(0x80)
+			ram.SetByte(0xFF80, 0x11)
+
+			len, cycles := set.NoPrefix[0xF2]()
+
+			assert.Equal(t, regs.AF.Hi(), byte(0x11))
+			assert.Equal(t, len, 1)
+			assert.Equal(t, cycles, 8)
+		})
+
+		t.Run("ADD SP,r8", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(regs.PC.HiLo() + 2)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.SP.Set(0x00FF)
+			ram.SetByte(regs.PC.HiLo()+1, 0xFF) // -1
+
+			len, cycles := set.NoPrefix[0xE8]()
+
+			assert.Equal(t, regs.SP.HiLo(), uint16(0x00FE))
+			assert.Equal(t, regs.Z(), false)
+			assert.Equal(t, regs.N(), false)
+			assert.Equal(t, regs.H(), true)
+			assert.Equal(t, regs.C(), true)
+			assert.Equal(t, len, 2)
+			assert.Equal(t, cycles, 16)
+		})
+
+		t.Run("LD HL,SP+r8", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(regs.PC.HiLo() + 2)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.SP.Set(0xFFF0)
+			ram.SetByte(regs.PC.HiLo()+1, 0x02)
+
+			len, cycles := set.NoPrefix[0xF8]()
+
+			assert.Equal(t, regs.HL.HiLo(), uint16(0xFFF2))
+			assert.Equal(t, regs.H(), false)
+			assert.Equal(t, regs.C(), false)
+			assert.Equal(t, len, 2)
+			assert.Equal(t, cycles, 12)
+		})
+
+		t.Run("JP (HL)", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(0)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.HL.Set(0x2000)
+
+			len, cycles := set.NoPrefix[0xE9]()
+
+			assert.Equal(t, regs.PC.HiLo()+uint16(len), uint16(0x2000))
+			assert.Equal(t, cycles, 4)
+		})
+
+		t.Run("DI and EI", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(0)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			set.NoPrefix[0xF3]()
+			assert.Equal(t, stateMgr.ime, false)
+
+			// EI takes effect after the next instruction.
+			set.NoPrefix[0xFB]()
+			stateMgr.step()
+			assert.Equal(t, stateMgr.ime, false)
+			stateMgr.step()
+			assert.Equal(t, stateMgr.ime, true)
+		})
+
+		t.Run("illegal opcode", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(regs.PC.HiLo() + 1)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			set.NoPrefix[0xD3]()
+
+			err := set.takeErr()
+			assert.Equal(t, err.Code, IllegalOpCode)
+			assert.Equal(t, set.takeErr(), (*errors.Error)(nil))
+		})
+	})
+
+	t.Run("CB prefix", func(t *testing.T) {
+		t.Run("shifts", func(t *testing.T) {
+			tests := []struct {
+				name   string
+				opcode byte
+				value  byte
+				carry  bool
+				want   byte
+				flags  [4]bool // Z, N, H, C
+			}{
+				{"RLC B", 0x00, 0x85, false, 0x0B, [4]bool{false, false, false, true}},
+				{"RRC B", 0x08, 0x01, false, 0x80, [4]bool{false, false, false, true}},
+				{"RL B", 0x10, 0x80, false, 0x00, [4]bool{true, false, false, true}},
+				{"RL B with carry", 0x10, 0x00, true, 0x01, [4]bool{false, false, false, false}},
+				{"RR B", 0x18, 0x01, true, 0x80, [4]bool{false, false, false, true}},
+				{"SLA B", 0x20, 0xFF, false, 0xFE, [4]bool{false, false, false, true}},
+				{"SRA B", 0x28, 0x81, false, 0xC0, [4]bool{false, false, false, true}},
+				{"SWAP B", 0x30, 0xF1, true, 0x1F, [4]bool{false, false, false, false}},
+				{"SRL B", 0x38, 0x81, false, 0x40, [4]bool{false, false, false, true}},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					regs := NewRegs()
+					ram := mem.NewRAM(0)
+					stateMgr := NewStateMgr()
+					set := NewInstrSet(regs, ram, stateMgr)
+
+					regs.BC.SetHi(tt.value)
+					regs.SetC(tt.carry)
+
+					len, cycles := set.CBPrefix[tt.opcode]()
+
+					assert.Equal(t, regs.BC.Hi(), tt.want)
+					assert.Equal(t, [4]bool{regs.Z(), regs.N(), regs.H(), regs.C()}, tt.flags)
+					assert.Equal(t, len, 2)
+					assert.Equal(t, cycles, 8)
+				})
+			}
+		})
+
+		t.Run("SWAP (HL)", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(1)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.HL.Set(0x0000)
+			ram.SetByte(0x0000, 0x12)
+
+			len, cycles := set.CBPrefix[0x36]()
+
+			got, _ := ram.GetByte(0x0000)
+			assert.Equal(t, got, byte(0x21))
+			assert.Equal(t, len, 2)
+			assert.Equal(t, cycles, 16)
+		})
+
+		t.Run("BIT 7,H", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(0)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.HL.SetHi(0x7F)
+			regs.SetC(true)
+
+			len, cycles := set.CBPrefix[0x7C]()
+
+			assert.Equal(t, regs.Z(), true)
+			assert.Equal(t, regs.N(), false)
+			assert.Equal(t, regs.H(), true)
+			assert.Equal(t, regs.C(), true)
+			assert.Equal(t, len, 2)
+			assert.Equal(t, cycles, 8)
+		})
+
+		t.Run("BIT 0,(HL)", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(1)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.HL.Set(0x0000)
+			ram.SetByte(0x0000, 0x01)
+
+			len, cycles := set.CBPrefix[0x46]()
+
+			assert.Equal(t, regs.Z(), false)
+			assert.Equal(t, len, 2)
+			assert.Equal(t, cycles, 12)
+		})
+
+		t.Run("RES 0,A", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(0)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.AF.SetHi(0xFF)
+
+			len, cycles := set.CBPrefix[0x87]()
+
+			assert.Equal(t, regs.AF.Hi(), byte(0xFE))
+			assert.Equal(t, len, 2)
+			assert.Equal(t, cycles, 8)
+		})
+
+		t.Run("SET 7,(HL)", func(t *testing.T) {
+			regs := NewRegs()
+			ram := mem.NewRAM(1)
+			stateMgr := NewStateMgr()
+			set := NewInstrSet(regs, ram, stateMgr)
+
+			regs.HL.Set(0x0000)
+
+			len, cycles := set.CBPrefix[0xFE]()
+
+			got, _ := ram.GetByte(0x0000)
+			assert.Equal(t, got, byte(0x80))
+			assert.Equal(t, len, 2)
+			assert.Equal(t, cycles, 16)
+		})
+
+		t.Run("complete", func(t *testing.T) {
+			set := NewInstrSet(NewRegs(), mem.NewRAM(0), NewStateMgr())
+			assert.Equal(t, len(set.NoPrefix), 256)
+			assert.Equal(t, len(set.CBPrefix), 256)
+		})
 	})
 }
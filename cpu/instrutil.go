@@ -1,17 +1,37 @@
 package cpu
 
-import "github.com/lucactt/gameboy/mem"
+import (
+	"fmt"
+
+	"github.com/lucactt/gameboy/mem"
+	"github.com/lucactt/gameboy/util/errors"
+)
 
 type instrUtil struct {
 	regs *Regs
 	mem  mem.Mem
+
+	// err is the first error raised by the running instruction.
+	// Instructions can't return errors, so the CPU must check it
+	// (and reset it) after running each of them.
+	err *errors.Error
+}
+
+// fail records an error raised by the running instruction,
+// unless another error has already been recorded.
+func (u *instrUtil) fail(err *errors.Error) {
+	if u.err == nil {
+		u.err = err
+	}
 }
 
-// getByte is a wrapper for getting bytes from mem that panic if an error is returned.
+// getByte is a wrapper for getting bytes from mem that records the error, if any.
+// In that case, 0xFF is returned as it's the value of an open bus.
 func (u *instrUtil) getByte(addr uint16) byte {
 	res, err := u.mem.GetByte(addr)
 	if err != nil {
-		panic(err)
+		u.fail(errors.E(fmt.Sprintf("read addr %#04x failed", addr), UnmappedAddr, err, errors.CPU))
+		return 0xFF
 	}
 	return res
 }
@@ -22,14 +42,25 @@ func (u *instrUtil) getByteAtPC(offset uint16) byte {
 	return u.getByte(u.regs.PC.HiLo() + offset)
 }
 
-// setByte is a wrapper for setting bytes to mem that panic if an error is returned.
+// setByte is a wrapper for setting bytes to mem that records the error, if any.
 func (u *instrUtil) setByte(addr uint16, value byte) {
 	err := u.mem.SetByte(addr, value)
 	if err != nil {
-		panic(err)
+		u.fail(errors.E(fmt.Sprintf("write addr %#04x failed", addr), UnmappedAddr, err, errors.CPU))
 	}
 }
 
+// canWrite checks that the memory accepts a write at the given address,
+// and records an error if it doesn't. It is used by the instructions that
+// write two bytes, to avoid writing only one of them.
+func (u *instrUtil) canWrite(addr uint16) bool {
+	if !u.mem.Accepts(addr) {
+		u.fail(errors.E(fmt.Sprintf("write addr %#04x failed", addr), UnmappedAddr, errors.CPU))
+		return false
+	}
+	return true
+}
+
 // inc8 increments an 8 bit register and also sets the correct flags.
 func (u *instrUtil) inc8(original byte, set func(byte)) (int, int) {
 	res := original + 1
@@ -91,3 +122,321 @@ func (u *instrUtil) add16(original, a uint16, set func(uint16)) (int, int) {
 
 	return 1, 8
 }
+
+// getD16AtPC gets the 16 bit immediate value found in the two bytes
+// after PC, where the first one is the least significant byte.
+func (u *instrUtil) getD16AtPC() uint16 {
+	return uint16(u.getByteAtPC(2))<<8 | uint16(u.getByteAtPC(1))
+}
+
+// ld8 puts an 8 bit value into an 8 bit register.
+func (u *instrUtil) ld8(value byte, set func(byte)) (int, int) {
+	set(value)
+	return 1, 4
+}
+
+// add8 adds a value to A and sets the correct flags.
+func (u *instrUtil) add8(value byte) (int, int) {
+	return u.adc8(value, false)
+}
+
+// adc8 adds a value and the carry (if enabled) to A, and sets the correct flags.
+func (u *instrUtil) adc8(value byte, useCarry bool) (int, int) {
+	a := u.regs.AF.Hi()
+	var carry byte
+	if useCarry && u.regs.C() {
+		carry = 1
+	}
+	res := uint16(a) + uint16(value) + uint16(carry)
+	u.regs.AF.SetHi(byte(res))
+
+	u.regs.SetZ(byte(res) == 0)
+	u.regs.SetN(false)
+	u.regs.SetH((a&0x0F)+(value&0x0F)+carry > 0x0F)
+	u.regs.SetC(res > 0xFF)
+
+	return 1, 4
+}
+
+// sub8 subtracts a value from A and sets the correct flags.
+func (u *instrUtil) sub8(value byte) (int, int) {
+	return u.sbc8(value, false)
+}
+
+// sbc8 subtracts a value and the carry (if enabled) from A, and sets the correct flags.
+func (u *instrUtil) sbc8(value byte, useCarry bool) (int, int) {
+	a := u.regs.AF.Hi()
+	var carry byte
+	if useCarry && u.regs.C() {
+		carry = 1
+	}
+	res := int16(a) - int16(value) - int16(carry)
+	u.regs.AF.SetHi(byte(res))
+
+	u.regs.SetZ(byte(res) == 0)
+	u.regs.SetN(true)
+	// There is a borrow from the 4th bit if the lower nibble
+	// of A is smaller than the lower nibble of the subtrahend.
+	u.regs.SetH(int16(a&0x0F)-int16(value&0x0F)-int16(carry) < 0)
+	u.regs.SetC(res < 0)
+
+	return 1, 4
+}
+
+// and8 does a bitwise AND between A and a value, and sets the correct flags.
+func (u *instrUtil) and8(value byte) (int, int) {
+	res := u.regs.AF.Hi() & value
+	u.regs.AF.SetHi(res)
+
+	u.regs.SetZ(res == 0)
+	u.regs.SetN(false)
+	u.regs.SetH(true)
+	u.regs.SetC(false)
+
+	return 1, 4
+}
+
+// xor8 does a bitwise XOR between A and a value, and sets the correct flags.
+func (u *instrUtil) xor8(value byte) (int, int) {
+	res := u.regs.AF.Hi() ^ value
+	u.regs.AF.SetHi(res)
+
+	u.regs.SetZ(res == 0)
+	u.regs.SetN(false)
+	u.regs.SetH(false)
+	u.regs.SetC(false)
+
+	return 1, 4
+}
+
+// or8 does a bitwise OR between A and a value, and sets the correct flags.
+func (u *instrUtil) or8(value byte) (int, int) {
+	res := u.regs.AF.Hi() | value
+	u.regs.AF.SetHi(res)
+
+	u.regs.SetZ(res == 0)
+	u.regs.SetN(false)
+	u.regs.SetH(false)
+	u.regs.SetC(false)
+
+	return 1, 4
+}
+
+// cp8 compares A with a value by subtracting the latter
+// from the former, without storing the result.
+func (u *instrUtil) cp8(value byte) (int, int) {
+	a := u.regs.AF.Hi()
+	u.sub8(value)
+	u.regs.AF.SetHi(a)
+
+	return 1, 4
+}
+
+// addSPr8 returns the sum of SP and the signed 8 bit immediate value,
+// and sets the flags as done by ADD SP,r8 and LD HL,SP+r8.
+func (u *instrUtil) addSPr8() uint16 {
+	sp := u.regs.SP.HiLo()
+	value := u.getByteAtPC(1)
+
+	u.regs.SetZ(false)
+	u.regs.SetN(false)
+	// The carries are computed on the lower byte,
+	// treating the immediate value as unsigned.
+	u.regs.SetH((sp&0x0F)+uint16(value&0x0F) > 0x0F)
+	u.regs.SetC((sp&0xFF)+uint16(value) > 0xFF)
+
+	return sp + uint16(int8(value))
+}
+
+// push puts a 16 bit value on top of the stack.
+func (u *instrUtil) push(value uint16) (int, int) {
+	sp := u.regs.SP.HiLo()
+	if !u.canWrite(sp-1) || !u.canWrite(sp-2) {
+		return 1, 16
+	}
+
+	u.regs.SP.Set(u.regs.SP.HiLo() - 1)
+	u.setByte(u.regs.SP.HiLo(), byte(value>>8))
+	u.regs.SP.Set(u.regs.SP.HiLo() - 1)
+	u.setByte(u.regs.SP.HiLo(), byte(value&0xFF))
+
+	return 1, 16
+}
+
+// pop removes a 16 bit value from the top of the stack
+// and passes it to the given setter.
+func (u *instrUtil) pop(set func(uint16)) (int, int) {
+	// Popping the two bytes would read past the top of the memory.
+	// With SP at 0xFFFE the bytes are still valid, and SP wraps to 0x0000.
+	if u.regs.SP.HiLo() == 0xFFFF {
+		u.fail(errors.E("stack underflow", StackUnderflow, errors.CPU))
+		return 1, 12
+	}
+
+	lo := u.getByte(u.regs.SP.HiLo())
+	u.regs.SP.Set(u.regs.SP.HiLo() + 1)
+	hi := u.getByte(u.regs.SP.HiLo())
+	u.regs.SP.Set(u.regs.SP.HiLo() + 1)
+	set(uint16(hi)<<8 | uint16(lo))
+
+	return 1, 12
+}
+
+// jump sets PC to the given address. As the CPU adds the length
+// of the instruction to PC after running it, the length is
+// subtracted from the address.
+func (u *instrUtil) jump(addr uint16, len int) {
+	u.regs.PC.Set(addr - uint16(len))
+}
+
+// jr adds the signed 8 bit immediate value to PC if the condition is true.
+func (u *instrUtil) jr(cond bool) (int, int) {
+	if !cond {
+		return 2, 8
+	}
+
+	// The offset is relative to the address of the next instruction,
+	// which is the one the CPU will add to PC.
+	offset := int8(u.getByteAtPC(1))
+	u.regs.PC.Set(u.regs.PC.HiLo() + uint16(offset))
+	return 2, 12
+}
+
+// jp jumps to the 16 bit immediate address if the condition is true.
+func (u *instrUtil) jp(cond bool) (int, int) {
+	if !cond {
+		return 3, 12
+	}
+
+	u.jump(u.getD16AtPC(), 3)
+	return 3, 16
+}
+
+// call pushes the address of the next instruction on the stack and
+// jumps to the 16 bit immediate address, if the condition is true.
+func (u *instrUtil) call(cond bool) (int, int) {
+	if !cond {
+		return 3, 12
+	}
+
+	addr := u.getD16AtPC()
+	u.push(u.regs.PC.HiLo() + 3)
+	u.jump(addr, 3)
+	return 3, 24
+}
+
+// ret pops the return address from the stack and jumps to it.
+func (u *instrUtil) ret() (int, int) {
+	u.pop(func(addr uint16) { u.jump(addr, 1) })
+	return 1, 16
+}
+
+// retCond returns from a call if the condition is true.
+// Note that it takes 4 more cycles than an unconditional RET.
+func (u *instrUtil) retCond(cond bool) (int, int) {
+	if !cond {
+		return 1, 8
+	}
+
+	u.ret()
+	return 1, 20
+}
+
+// rst pushes the address of the next instruction on the stack
+// and jumps to the given address.
+func (u *instrUtil) rst(addr uint16) (int, int) {
+	u.push(u.regs.PC.HiLo() + 1)
+	u.jump(addr, 1)
+	return 1, 16
+}
+
+// illegal is used for the opcodes that don't exist in the
+// Gameboy CPU, which would lock the hardware.
+func (u *instrUtil) illegal() (int, int) {
+	u.fail(errors.E(fmt.Sprintf("illegal opcode %#02x", u.getByteAtPC(0)), IllegalOpCode, errors.CPU))
+	return 1, 4
+}
+
+// rlc rotates a value left, putting the old 7th bit in
+// the 0th bit and in the carry flag.
+func (u *instrUtil) rlc(original byte, set func(byte)) (int, int) {
+	return u.shift(original<<1|original>>7, original>>7 == 1, set)
+}
+
+// rrc rotates a value right, putting the old 0th bit in
+// the 7th bit and in the carry flag.
+func (u *instrUtil) rrc(original byte, set func(byte)) (int, int) {
+	return u.shift(original>>1|original<<7, original&0x01 == 1, set)
+}
+
+// rl rotates a value left through the carry flag.
+func (u *instrUtil) rl(original byte, set func(byte)) (int, int) {
+	var carry byte
+	if u.regs.C() {
+		carry = 1
+	}
+	return u.shift(original<<1|carry, original>>7 == 1, set)
+}
+
+// rr rotates a value right through the carry flag.
+func (u *instrUtil) rr(original byte, set func(byte)) (int, int) {
+	var carry byte
+	if u.regs.C() {
+		carry = 1
+	}
+	return u.shift(original>>1|carry<<7, original&0x01 == 1, set)
+}
+
+// sla shifts a value left into the carry flag. The 0th bit is reset.
+func (u *instrUtil) sla(original byte, set func(byte)) (int, int) {
+	return u.shift(original<<1, original>>7 == 1, set)
+}
+
+// sra shifts a value right into the carry flag. The 7th bit is unchanged.
+func (u *instrUtil) sra(original byte, set func(byte)) (int, int) {
+	return u.shift(original>>1|original&0x80, original&0x01 == 1, set)
+}
+
+// srl shifts a value right into the carry flag. The 7th bit is reset.
+func (u *instrUtil) srl(original byte, set func(byte)) (int, int) {
+	return u.shift(original>>1, original&0x01 == 1, set)
+}
+
+// swap swaps the upper and lower nibbles of a value.
+func (u *instrUtil) swap(original byte, set func(byte)) (int, int) {
+	return u.shift(original<<4|original>>4, false, set)
+}
+
+// shift sets the result of a CB-prefixed rotate or shift instruction
+// and the correct flags.
+func (u *instrUtil) shift(res byte, carry bool, set func(byte)) (int, int) {
+	set(res)
+
+	u.regs.SetZ(res == 0)
+	u.regs.SetN(false)
+	u.regs.SetH(false)
+	u.regs.SetC(carry)
+
+	return 2, 8
+}
+
+// bit tests the n-th bit of a value, setting Z if it is 0.
+func (u *instrUtil) bit(n uint, value byte) (int, int) {
+	u.regs.SetZ(value&(1<<n) == 0)
+	u.regs.SetN(false)
+	u.regs.SetH(true)
+
+	return 2, 8
+}
+
+// resBit resets the n-th bit of a value.
+func (u *instrUtil) resBit(n uint, original byte, set func(byte)) (int, int) {
+	set(original &^ (1 << n))
+	return 2, 8
+}
+
+// setBit sets the n-th bit of a value.
+func (u *instrUtil) setBit(n uint, original byte, set func(byte)) (int, int) {
+	set(original | (1 << n))
+	return 2, 8
+}
@@ -0,0 +1,106 @@
+package cpu
+
+import (
+	"github.com/lucactt/gameboy/mem"
+)
+
+// Interrupt identifies one of the interrupts that can
+// be requested to the CPU. Its value is the position of
+// the interrupt bit in the IE and IF registers.
+type Interrupt byte
+
+// Interrupts supported by the CPU, in priority order.
+const (
+	VBlank Interrupt = iota
+	LCDStat
+	Timer
+	Serial
+	Joypad
+)
+
+// Addresses of the interrupt registers.
+const (
+	IFAddr uint16 = 0xFF0F
+	IEAddr uint16 = 0xFFFF
+)
+
+const (
+	// interruptsMask selects the bits of IE and IF that correspond to an interrupt.
+	interruptsMask byte = 0x1F
+
+	// interruptCycles is the number of cycles used to dispatch an interrupt.
+	interruptCycles int = 20
+)
+
+// Vector returns the address of the handler of the interrupt.
+func (i Interrupt) Vector() uint16 {
+	return 0x0040 + uint16(i)*0x08
+}
+
+// Interrupts manages the Interrupt Enable (IE) and the
+// Interrupt Flag (IF) registers.
+//
+// Peripherals use Request to raise an interrupt, which the
+// CPU will service if it is enabled in IE.
+type Interrupts struct {
+	enable byte
+	flag   byte
+}
+
+// NewInterrupts creates a new Interrupts with no enabled or requested interrupt.
+func NewInterrupts() *Interrupts {
+	return &Interrupts{}
+}
+
+// Request sets the IF bit of the given interrupt.
+func (i *Interrupts) Request(n Interrupt) {
+	i.flag |= 1 << n
+}
+
+// Pending returns true if any of the requested interrupts is also enabled.
+func (i *Interrupts) Pending() bool {
+	return i.enable&i.flag&interruptsMask != 0
+}
+
+// requested returns true if the IF bit of the given interrupt is set.
+func (i *Interrupts) requested(n Interrupt) bool {
+	return i.flag&(1<<n) != 0
+}
+
+// next returns the pending interrupt with the highest priority.
+func (i *Interrupts) next() (Interrupt, bool) {
+	pending := i.enable & i.flag & interruptsMask
+	for n := VBlank; n <= Joypad; n++ {
+		if pending&(1<<n) != 0 {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// ack clears the IF bit of the given interrupt.
+func (i *Interrupts) ack(n Interrupt) {
+	i.flag &^= 1 << n
+}
+
+// IE returns the IE register, which should be registered
+// in a mem.IO mapped at IEAddr.
+func (i *Interrupts) IE() mem.Reg {
+	return mem.Reg{
+		Name:  "IE",
+		Read:  func() byte { return i.enable },
+		Write: func(value byte) { i.enable = value },
+	}
+}
+
+// IF returns the IF register, which should be registered
+// in a mem.IO mapped at IFAddr.
+func (i *Interrupts) IF() mem.Reg {
+	// The upper 3 bits of IF are unused and always read as 1.
+	return mem.Reg{
+		Name:   "IF",
+		Unused: ^interruptsMask,
+		Read:   func() byte { return i.flag },
+		Write:  func(value byte) { i.flag = value },
+	}
+}
@@ -0,0 +1,95 @@
+package cpu
+
+import (
+	"testing"
+
+	"github.com/lucactt/gameboy/mem"
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+func TestInterrupt_Vector(t *testing.T) {
+	tests := []struct {
+		name string
+		n    Interrupt
+		want uint16
+	}{
+		{"VBlank", VBlank, 0x0040},
+		{"LCD STAT", LCDStat, 0x0048},
+		{"Timer", Timer, 0x0050},
+		{"Serial", Serial, 0x0058},
+		{"Joypad", Joypad, 0x0060},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.n.Vector(), tt.want)
+		})
+	}
+}
+
+func TestInterrupts_Pending(t *testing.T) {
+	t.Run("requested but not enabled", func(t *testing.T) {
+		i := NewInterrupts()
+		i.Request(Timer)
+
+		assert.Equal(t, i.Pending(), false)
+	})
+
+	t.Run("requested and enabled", func(t *testing.T) {
+		i := NewInterrupts()
+		i.IE().Write(0x04)
+		i.Request(Timer)
+
+		assert.Equal(t, i.Pending(), true)
+	})
+}
+
+func TestInterrupts_next(t *testing.T) {
+	i := NewInterrupts()
+	i.IE().Write(0xFF)
+	i.Request(Joypad)
+	i.Request(LCDStat)
+
+	got, ok := i.next()
+	assert.Equal(t, ok, true)
+	assert.Equal(t, got, LCDStat)
+
+	i.ack(LCDStat)
+
+	got, ok = i.next()
+	assert.Equal(t, ok, true)
+	assert.Equal(t, got, Joypad)
+}
+
+func TestInterrupts_IF(t *testing.T) {
+	i := NewInterrupts()
+	io := mem.NewIO(1)
+	io.Register(0x0000, i.IF())
+
+	t.Run("unused bits", func(t *testing.T) {
+		i.Request(VBlank)
+
+		got, err := io.GetByte(0x0000)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0xE1))
+	})
+
+	t.Run("write", func(t *testing.T) {
+		err := io.SetByte(0x0000, 0xFF)
+		assert.Err(t, err, false)
+		assert.Equal(t, i.flag, byte(0x1F))
+	})
+}
+
+func TestInterrupts_IE(t *testing.T) {
+	i := NewInterrupts()
+	io := mem.NewIO(1)
+	io.Register(0x0000, i.IE())
+
+	err := io.SetByte(0x0000, 0xFF)
+	assert.Err(t, err, false)
+
+	got, _ := io.GetByte(0x0000)
+	assert.Equal(t, got, byte(0xFF))
+	assert.Equal(t, i.enable, byte(0xFF))
+}
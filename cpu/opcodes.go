@@ -0,0 +1,640 @@
+package cpu
+
+import "strings"
+
+// Operand is an operand of an instruction, as written in its mnemonic.
+//
+// Registers and conditions are written by name, for example "HL" or "NZ".
+// Operands between parentheses are addresses in memory.
+type Operand string
+
+// Operands that refer to immediate values, found in the bytes after the opcode.
+const (
+	D8     Operand = "d8"    // 8 bit unsigned value
+	D16    Operand = "d16"   // 16 bit unsigned value
+	A16    Operand = "a16"   // 16 bit address
+	R8     Operand = "r8"    // 8 bit signed value
+	IndA8  Operand = "(a8)"  // Memory at 0xFF00 plus an 8 bit unsigned value
+	IndA16 Operand = "(a16)" // Memory at a 16 bit address
+	SPR8   Operand = "SP+r8" // SP plus an 8 bit signed value
+)
+
+// ImmSize returns the number of immediate bytes used by the operand.
+func (o Operand) ImmSize() int {
+	switch o {
+	case D8, R8, IndA8, SPR8:
+		return 1
+	case D16, A16, IndA16:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// Indirect returns true if the operand refers to a memory address.
+func (o Operand) Indirect() bool {
+	return strings.HasPrefix(string(o), "(")
+}
+
+// FlagEffect describes how an instruction affects a flag.
+type FlagEffect byte
+
+// Flag effects.
+const (
+	FlagUnaffected FlagEffect = iota
+	FlagReset
+	FlagSet
+	FlagAffected // Depends on the result of the instruction.
+)
+
+// Flags describes how an instruction affects the Z, N, H and C flags,
+// with a character for each of them in this order: '-' if the flag is unaffected,
+// '0' if it's reset, '1' if it's set, or the flag name if it depends on the result.
+type Flags string
+
+// Z returns the effect on the zero flag.
+func (f Flags) Z() FlagEffect {
+	return f.effect(0)
+}
+
+// N returns the effect on the subtract flag.
+func (f Flags) N() FlagEffect {
+	return f.effect(1)
+}
+
+// H returns the effect on the half carry flag.
+func (f Flags) H() FlagEffect {
+	return f.effect(2)
+}
+
+// C returns the effect on the carry flag.
+func (f Flags) C() FlagEffect {
+	return f.effect(3)
+}
+
+func (f Flags) effect(i int) FlagEffect {
+	switch f[i] {
+	case '-':
+		return FlagUnaffected
+	case '0':
+		return FlagReset
+	case '1':
+		return FlagSet
+	default:
+		return FlagAffected
+	}
+}
+
+// OpCode describes an instruction of the CPU.
+type OpCode struct {
+	Mnemonic string
+	Operands []Operand
+
+	// Length is the number of bytes of the instruction, including the opcode.
+	Length int
+
+	// Cycles is the number of clock cycles used by the instruction,
+	// or by a conditional branch when it's not taken.
+	Cycles int
+
+	// CyclesTaken is the number of clock cycles used by a conditional
+	// branch when it's taken, and 0 for any other instruction.
+	CyclesTaken int
+
+	Flags Flags
+}
+
+// String returns the instruction as it's written in assembly, e.g. "LD A,(HL+)".
+func (o OpCode) String() string {
+	if len(o.Operands) == 0 {
+		return o.Mnemonic
+	}
+
+	ops := make([]string, len(o.Operands))
+	for i, op := range o.Operands {
+		ops[i] = string(op)
+	}
+	return o.Mnemonic + " " + strings.Join(ops, ",")
+}
+
+// NoPrefixOpCodes describes the non-prefixed instructions, indexed by opcode.
+// The illegal opcodes have the ILLEGAL mnemonic.
+var NoPrefixOpCodes = [256]OpCode{
+	0x00: {"NOP", nil, 1, 4, 0, "----"},
+	0x01: {"LD", []Operand{"BC", D16}, 3, 12, 0, "----"},
+	0x02: {"LD", []Operand{"(BC)", "A"}, 1, 8, 0, "----"},
+	0x03: {"INC", []Operand{"BC"}, 1, 8, 0, "----"},
+	0x04: {"INC", []Operand{"B"}, 1, 4, 0, "Z0H-"},
+	0x05: {"DEC", []Operand{"B"}, 1, 4, 0, "Z1H-"},
+	0x06: {"LD", []Operand{"B", D8}, 2, 8, 0, "----"},
+	0x07: {"RLCA", nil, 1, 4, 0, "000C"},
+	0x08: {"LD", []Operand{IndA16, "SP"}, 3, 20, 0, "----"},
+	0x09: {"ADD", []Operand{"HL", "BC"}, 1, 8, 0, "-0HC"},
+	0x0A: {"LD", []Operand{"A", "(BC)"}, 1, 8, 0, "----"},
+	0x0B: {"DEC", []Operand{"BC"}, 1, 8, 0, "----"},
+	0x0C: {"INC", []Operand{"C"}, 1, 4, 0, "Z0H-"},
+	0x0D: {"DEC", []Operand{"C"}, 1, 4, 0, "Z1H-"},
+	0x0E: {"LD", []Operand{"C", D8}, 2, 8, 0, "----"},
+	0x0F: {"RRCA", nil, 1, 4, 0, "000C"},
+	0x10: {"STOP", nil, 2, 4, 0, "----"},
+	0x11: {"LD", []Operand{"DE", D16}, 3, 12, 0, "----"},
+	0x12: {"LD", []Operand{"(DE)", "A"}, 1, 8, 0, "----"},
+	0x13: {"INC", []Operand{"DE"}, 1, 8, 0, "----"},
+	0x14: {"INC", []Operand{"D"}, 1, 4, 0, "Z0H-"},
+	0x15: {"DEC", []Operand{"D"}, 1, 4, 0, "Z1H-"},
+	0x16: {"LD", []Operand{"D", D8}, 2, 8, 0, "----"},
+	0x17: {"RLA", nil, 1, 4, 0, "000C"},
+	0x18: {"JR", []Operand{R8}, 2, 12, 0, "----"},
+	0x19: {"ADD", []Operand{"HL", "DE"}, 1, 8, 0, "-0HC"},
+	0x1A: {"LD", []Operand{"A", "(DE)"}, 1, 8, 0, "----"},
+	0x1B: {"DEC", []Operand{"DE"}, 1, 8, 0, "----"},
+	0x1C: {"INC", []Operand{"E"}, 1, 4, 0, "Z0H-"},
+	0x1D: {"DEC", []Operand{"E"}, 1, 4, 0, "Z1H-"},
+	0x1E: {"LD", []Operand{"E", D8}, 2, 8, 0, "----"},
+	0x1F: {"RRA", nil, 1, 4, 0, "000C"},
+	0x20: {"JR", []Operand{"NZ", R8}, 2, 8, 12, "----"},
+	0x21: {"LD", []Operand{"HL", D16}, 3, 12, 0, "----"},
+	0x22: {"LD", []Operand{"(HL+)", "A"}, 1, 8, 0, "----"},
+	0x23: {"INC", []Operand{"HL"}, 1, 8, 0, "----"},
+	0x24: {"INC", []Operand{"H"}, 1, 4, 0, "Z0H-"},
+	0x25: {"DEC", []Operand{"H"}, 1, 4, 0, "Z1H-"},
+	0x26: {"LD", []Operand{"H", D8}, 2, 8, 0, "----"},
+	0x27: {"DAA", nil, 1, 4, 0, "Z-0C"},
+	0x28: {"JR", []Operand{"Z", R8}, 2, 8, 12, "----"},
+	0x29: {"ADD", []Operand{"HL", "HL"}, 1, 8, 0, "-0HC"},
+	0x2A: {"LD", []Operand{"A", "(HL+)"}, 1, 8, 0, "----"},
+	0x2B: {"DEC", []Operand{"HL"}, 1, 8, 0, "----"},
+	0x2C: {"INC", []Operand{"L"}, 1, 4, 0, "Z0H-"},
+	0x2D: {"DEC", []Operand{"L"}, 1, 4, 0, "Z1H-"},
+	0x2E: {"LD", []Operand{"L", D8}, 2, 8, 0, "----"},
+	0x2F: {"CPL", nil, 1, 4, 0, "-11-"},
+	0x30: {"JR", []Operand{"NC", R8}, 2, 8, 12, "----"},
+	0x31: {"LD", []Operand{"SP", D16}, 3, 12, 0, "----"},
+	0x32: {"LD", []Operand{"(HL-)", "A"}, 1, 8, 0, "----"},
+	0x33: {"INC", []Operand{"SP"}, 1, 8, 0, "----"},
+	0x34: {"INC", []Operand{"(HL)"}, 1, 12, 0, "Z0H-"},
+	0x35: {"DEC", []Operand{"(HL)"}, 1, 12, 0, "Z1H-"},
+	0x36: {"LD", []Operand{"(HL)", D8}, 2, 12, 0, "----"},
+	0x37: {"SCF", nil, 1, 4, 0, "-001"},
+	0x38: {"JR", []Operand{"C", R8}, 2, 8, 12, "----"},
+	0x39: {"ADD", []Operand{"HL", "SP"}, 1, 8, 0, "-0HC"},
+	0x3A: {"LD", []Operand{"A", "(HL-)"}, 1, 8, 0, "----"},
+	0x3B: {"DEC", []Operand{"SP"}, 1, 8, 0, "----"},
+	0x3C: {"INC", []Operand{"A"}, 1, 4, 0, "Z0H-"},
+	0x3D: {"DEC", []Operand{"A"}, 1, 4, 0, "Z1H-"},
+	0x3E: {"LD", []Operand{"A", D8}, 2, 8, 0, "----"},
+	0x3F: {"CCF", nil, 1, 4, 0, "-00C"},
+	0x40: {"LD", []Operand{"B", "B"}, 1, 4, 0, "----"},
+	0x41: {"LD", []Operand{"B", "C"}, 1, 4, 0, "----"},
+	0x42: {"LD", []Operand{"B", "D"}, 1, 4, 0, "----"},
+	0x43: {"LD", []Operand{"B", "E"}, 1, 4, 0, "----"},
+	0x44: {"LD", []Operand{"B", "H"}, 1, 4, 0, "----"},
+	0x45: {"LD", []Operand{"B", "L"}, 1, 4, 0, "----"},
+	0x46: {"LD", []Operand{"B", "(HL)"}, 1, 8, 0, "----"},
+	0x47: {"LD", []Operand{"B", "A"}, 1, 4, 0, "----"},
+	0x48: {"LD", []Operand{"C", "B"}, 1, 4, 0, "----"},
+	0x49: {"LD", []Operand{"C", "C"}, 1, 4, 0, "----"},
+	0x4A: {"LD", []Operand{"C", "D"}, 1, 4, 0, "----"},
+	0x4B: {"LD", []Operand{"C", "E"}, 1, 4, 0, "----"},
+	0x4C: {"LD", []Operand{"C", "H"}, 1, 4, 0, "----"},
+	0x4D: {"LD", []Operand{"C", "L"}, 1, 4, 0, "----"},
+	0x4E: {"LD", []Operand{"C", "(HL)"}, 1, 8, 0, "----"},
+	0x4F: {"LD", []Operand{"C", "A"}, 1, 4, 0, "----"},
+	0x50: {"LD", []Operand{"D", "B"}, 1, 4, 0, "----"},
+	0x51: {"LD", []Operand{"D", "C"}, 1, 4, 0, "----"},
+	0x52: {"LD", []Operand{"D", "D"}, 1, 4, 0, "----"},
+	0x53: {"LD", []Operand{"D", "E"}, 1, 4, 0, "----"},
+	0x54: {"LD", []Operand{"D", "H"}, 1, 4, 0, "----"},
+	0x55: {"LD", []Operand{"D", "L"}, 1, 4, 0, "----"},
+	0x56: {"LD", []Operand{"D", "(HL)"}, 1, 8, 0, "----"},
+	0x57: {"LD", []Operand{"D", "A"}, 1, 4, 0, "----"},
+	0x58: {"LD", []Operand{"E", "B"}, 1, 4, 0, "----"},
+	0x59: {"LD", []Operand{"E", "C"}, 1, 4, 0, "----"},
+	0x5A: {"LD", []Operand{"E", "D"}, 1, 4, 0, "----"},
+	0x5B: {"LD", []Operand{"E", "E"}, 1, 4, 0, "----"},
+	0x5C: {"LD", []Operand{"E", "H"}, 1, 4, 0, "----"},
+	0x5D: {"LD", []Operand{"E", "L"}, 1, 4, 0, "----"},
+	0x5E: {"LD", []Operand{"E", "(HL)"}, 1, 8, 0, "----"},
+	0x5F: {"LD", []Operand{"E", "A"}, 1, 4, 0, "----"},
+	0x60: {"LD", []Operand{"H", "B"}, 1, 4, 0, "----"},
+	0x61: {"LD", []Operand{"H", "C"}, 1, 4, 0, "----"},
+	0x62: {"LD", []Operand{"H", "D"}, 1, 4, 0, "----"},
+	0x63: {"LD", []Operand{"H", "E"}, 1, 4, 0, "----"},
+	0x64: {"LD", []Operand{"H", "H"}, 1, 4, 0, "----"},
+	0x65: {"LD", []Operand{"H", "L"}, 1, 4, 0, "----"},
+	0x66: {"LD", []Operand{"H", "(HL)"}, 1, 8, 0, "----"},
+	0x67: {"LD", []Operand{"H", "A"}, 1, 4, 0, "----"},
+	0x68: {"LD", []Operand{"L", "B"}, 1, 4, 0, "----"},
+	0x69: {"LD", []Operand{"L", "C"}, 1, 4, 0, "----"},
+	0x6A: {"LD", []Operand{"L", "D"}, 1, 4, 0, "----"},
+	0x6B: {"LD", []Operand{"L", "E"}, 1, 4, 0, "----"},
+	0x6C: {"LD", []Operand{"L", "H"}, 1, 4, 0, "----"},
+	0x6D: {"LD", []Operand{"L", "L"}, 1, 4, 0, "----"},
+	0x6E: {"LD", []Operand{"L", "(HL)"}, 1, 8, 0, "----"},
+	0x6F: {"LD", []Operand{"L", "A"}, 1, 4, 0, "----"},
+	0x70: {"LD", []Operand{"(HL)", "B"}, 1, 8, 0, "----"},
+	0x71: {"LD", []Operand{"(HL)", "C"}, 1, 8, 0, "----"},
+	0x72: {"LD", []Operand{"(HL)", "D"}, 1, 8, 0, "----"},
+	0x73: {"LD", []Operand{"(HL)", "E"}, 1, 8, 0, "----"},
+	0x74: {"LD", []Operand{"(HL)", "H"}, 1, 8, 0, "----"},
+	0x75: {"LD", []Operand{"(HL)", "L"}, 1, 8, 0, "----"},
+	0x76: {"HALT", nil, 1, 4, 0, "----"},
+	0x77: {"LD", []Operand{"(HL)", "A"}, 1, 8, 0, "----"},
+	0x78: {"LD", []Operand{"A", "B"}, 1, 4, 0, "----"},
+	0x79: {"LD", []Operand{"A", "C"}, 1, 4, 0, "----"},
+	0x7A: {"LD", []Operand{"A", "D"}, 1, 4, 0, "----"},
+	0x7B: {"LD", []Operand{"A", "E"}, 1, 4, 0, "----"},
+	0x7C: {"LD", []Operand{"A", "H"}, 1, 4, 0, "----"},
+	0x7D: {"LD", []Operand{"A", "L"}, 1, 4, 0, "----"},
+	0x7E: {"LD", []Operand{"A", "(HL)"}, 1, 8, 0, "----"},
+	0x7F: {"LD", []Operand{"A", "A"}, 1, 4, 0, "----"},
+	0x80: {"ADD", []Operand{"A", "B"}, 1, 4, 0, "Z0HC"},
+	0x81: {"ADD", []Operand{"A", "C"}, 1, 4, 0, "Z0HC"},
+	0x82: {"ADD", []Operand{"A", "D"}, 1, 4, 0, "Z0HC"},
+	0x83: {"ADD", []Operand{"A", "E"}, 1, 4, 0, "Z0HC"},
+	0x84: {"ADD", []Operand{"A", "H"}, 1, 4, 0, "Z0HC"},
+	0x85: {"ADD", []Operand{"A", "L"}, 1, 4, 0, "Z0HC"},
+	0x86: {"ADD", []Operand{"A", "(HL)"}, 1, 8, 0, "Z0HC"},
+	0x87: {"ADD", []Operand{"A", "A"}, 1, 4, 0, "Z0HC"},
+	0x88: {"ADC", []Operand{"A", "B"}, 1, 4, 0, "Z0HC"},
+	0x89: {"ADC", []Operand{"A", "C"}, 1, 4, 0, "Z0HC"},
+	0x8A: {"ADC", []Operand{"A", "D"}, 1, 4, 0, "Z0HC"},
+	0x8B: {"ADC", []Operand{"A", "E"}, 1, 4, 0, "Z0HC"},
+	0x8C: {"ADC", []Operand{"A", "H"}, 1, 4, 0, "Z0HC"},
+	0x8D: {"ADC", []Operand{"A", "L"}, 1, 4, 0, "Z0HC"},
+	0x8E: {"ADC", []Operand{"A", "(HL)"}, 1, 8, 0, "Z0HC"},
+	0x8F: {"ADC", []Operand{"A", "A"}, 1, 4, 0, "Z0HC"},
+	0x90: {"SUB", []Operand{"B"}, 1, 4, 0, "Z1HC"},
+	0x91: {"SUB", []Operand{"C"}, 1, 4, 0, "Z1HC"},
+	0x92: {"SUB", []Operand{"D"}, 1, 4, 0, "Z1HC"},
+	0x93: {"SUB", []Operand{"E"}, 1, 4, 0, "Z1HC"},
+	0x94: {"SUB", []Operand{"H"}, 1, 4, 0, "Z1HC"},
+	0x95: {"SUB", []Operand{"L"}, 1, 4, 0, "Z1HC"},
+	0x96: {"SUB", []Operand{"(HL)"}, 1, 8, 0, "Z1HC"},
+	0x97: {"SUB", []Operand{"A"}, 1, 4, 0, "Z1HC"},
+	0x98: {"SBC", []Operand{"A", "B"}, 1, 4, 0, "Z1HC"},
+	0x99: {"SBC", []Operand{"A", "C"}, 1, 4, 0, "Z1HC"},
+	0x9A: {"SBC", []Operand{"A", "D"}, 1, 4, 0, "Z1HC"},
+	0x9B: {"SBC", []Operand{"A", "E"}, 1, 4, 0, "Z1HC"},
+	0x9C: {"SBC", []Operand{"A", "H"}, 1, 4, 0, "Z1HC"},
+	0x9D: {"SBC", []Operand{"A", "L"}, 1, 4, 0, "Z1HC"},
+	0x9E: {"SBC", []Operand{"A", "(HL)"}, 1, 8, 0, "Z1HC"},
+	0x9F: {"SBC", []Operand{"A", "A"}, 1, 4, 0, "Z1HC"},
+	0xA0: {"AND", []Operand{"B"}, 1, 4, 0, "Z010"},
+	0xA1: {"AND", []Operand{"C"}, 1, 4, 0, "Z010"},
+	0xA2: {"AND", []Operand{"D"}, 1, 4, 0, "Z010"},
+	0xA3: {"AND", []Operand{"E"}, 1, 4, 0, "Z010"},
+	0xA4: {"AND", []Operand{"H"}, 1, 4, 0, "Z010"},
+	0xA5: {"AND", []Operand{"L"}, 1, 4, 0, "Z010"},
+	0xA6: {"AND", []Operand{"(HL)"}, 1, 8, 0, "Z010"},
+	0xA7: {"AND", []Operand{"A"}, 1, 4, 0, "Z010"},
+	0xA8: {"XOR", []Operand{"B"}, 1, 4, 0, "Z000"},
+	0xA9: {"XOR", []Operand{"C"}, 1, 4, 0, "Z000"},
+	0xAA: {"XOR", []Operand{"D"}, 1, 4, 0, "Z000"},
+	0xAB: {"XOR", []Operand{"E"}, 1, 4, 0, "Z000"},
+	0xAC: {"XOR", []Operand{"H"}, 1, 4, 0, "Z000"},
+	0xAD: {"XOR", []Operand{"L"}, 1, 4, 0, "Z000"},
+	0xAE: {"XOR", []Operand{"(HL)"}, 1, 8, 0, "Z000"},
+	0xAF: {"XOR", []Operand{"A"}, 1, 4, 0, "Z000"},
+	0xB0: {"OR", []Operand{"B"}, 1, 4, 0, "Z000"},
+	0xB1: {"OR", []Operand{"C"}, 1, 4, 0, "Z000"},
+	0xB2: {"OR", []Operand{"D"}, 1, 4, 0, "Z000"},
+	0xB3: {"OR", []Operand{"E"}, 1, 4, 0, "Z000"},
+	0xB4: {"OR", []Operand{"H"}, 1, 4, 0, "Z000"},
+	0xB5: {"OR", []Operand{"L"}, 1, 4, 0, "Z000"},
+	0xB6: {"OR", []Operand{"(HL)"}, 1, 8, 0, "Z000"},
+	0xB7: {"OR", []Operand{"A"}, 1, 4, 0, "Z000"},
+	0xB8: {"CP", []Operand{"B"}, 1, 4, 0, "Z1HC"},
+	0xB9: {"CP", []Operand{"C"}, 1, 4, 0, "Z1HC"},
+	0xBA: {"CP", []Operand{"D"}, 1, 4, 0, "Z1HC"},
+	0xBB: {"CP", []Operand{"E"}, 1, 4, 0, "Z1HC"},
+	0xBC: {"CP", []Operand{"H"}, 1, 4, 0, "Z1HC"},
+	0xBD: {"CP", []Operand{"L"}, 1, 4, 0, "Z1HC"},
+	0xBE: {"CP", []Operand{"(HL)"}, 1, 8, 0, "Z1HC"},
+	0xBF: {"CP", []Operand{"A"}, 1, 4, 0, "Z1HC"},
+	0xC0: {"RET", []Operand{"NZ"}, 1, 8, 20, "----"},
+	0xC1: {"POP", []Operand{"BC"}, 1, 12, 0, "----"},
+	0xC2: {"JP", []Operand{"NZ", A16}, 3, 12, 16, "----"},
+	0xC3: {"JP", []Operand{A16}, 3, 16, 0, "----"},
+	0xC4: {"CALL", []Operand{"NZ", A16}, 3, 12, 24, "----"},
+	0xC5: {"PUSH", []Operand{"BC"}, 1, 16, 0, "----"},
+	0xC6: {"ADD", []Operand{"A", D8}, 2, 8, 0, "Z0HC"},
+	0xC7: {"RST", []Operand{"00H"}, 1, 16, 0, "----"},
+	0xC8: {"RET", []Operand{"Z"}, 1, 8, 20, "----"},
+	0xC9: {"RET", nil, 1, 16, 0, "----"},
+	0xCA: {"JP", []Operand{"Z", A16}, 3, 12, 16, "----"},
+	0xCB: {"PREFIX", []Operand{"CB"}, 1, 4, 0, "----"},
+	0xCC: {"CALL", []Operand{"Z", A16}, 3, 12, 24, "----"},
+	0xCD: {"CALL", []Operand{A16}, 3, 24, 0, "----"},
+	0xCE: {"ADC", []Operand{"A", D8}, 2, 8, 0, "Z0HC"},
+	0xCF: {"RST", []Operand{"08H"}, 1, 16, 0, "----"},
+	0xD0: {"RET", []Operand{"NC"}, 1, 8, 20, "----"},
+	0xD1: {"POP", []Operand{"DE"}, 1, 12, 0, "----"},
+	0xD2: {"JP", []Operand{"NC", A16}, 3, 12, 16, "----"},
+	0xD3: {"ILLEGAL", nil, 1, 4, 0, "----"},
+	0xD4: {"CALL", []Operand{"NC", A16}, 3, 12, 24, "----"},
+	0xD5: {"PUSH", []Operand{"DE"}, 1, 16, 0, "----"},
+	0xD6: {"SUB", []Operand{D8}, 2, 8, 0, "Z1HC"},
+	0xD7: {"RST", []Operand{"10H"}, 1, 16, 0, "----"},
+	0xD8: {"RET", []Operand{"C"}, 1, 8, 20, "----"},
+	0xD9: {"RETI", nil, 1, 16, 0, "----"},
+	0xDA: {"JP", []Operand{"C", A16}, 3, 12, 16, "----"},
+	0xDB: {"ILLEGAL", nil, 1, 4, 0, "----"},
+	0xDC: {"CALL", []Operand{"C", A16}, 3, 12, 24, "----"},
+	0xDD: {"ILLEGAL", nil, 1, 4, 0, "----"},
+	0xDE: {"SBC", []Operand{"A", D8}, 2, 8, 0, "Z1HC"},
+	0xDF: {"RST", []Operand{"18H"}, 1, 16, 0, "----"},
+	0xE0: {"LDH", []Operand{IndA8, "A"}, 2, 12, 0, "----"},
+	0xE1: {"POP", []Operand{"HL"}, 1, 12, 0, "----"},
+	0xE2: {"LD", []Operand{"(C)", "A"}, 1, 8, 0, "----"},
+	0xE3: {"ILLEGAL", nil, 1, 4, 0, "----"},
+	0xE4: {"ILLEGAL", nil, 1, 4, 0, "----"},
+	0xE5: {"PUSH", []Operand{"HL"}, 1, 16, 0, "----"},
+	0xE6: {"AND", []Operand{D8}, 2, 8, 0, "Z010"},
+	0xE7: {"RST", []Operand{"20H"}, 1, 16, 0, "----"},
+	0xE8: {"ADD", []Operand{"SP", R8}, 2, 16, 0, "00HC"},
+	0xE9: {"JP", []Operand{"HL"}, 1, 4, 0, "----"},
+	0xEA: {"LD", []Operand{IndA16, "A"}, 3, 16, 0, "----"},
+	0xEB: {"ILLEGAL", nil, 1, 4, 0, "----"},
+	0xEC: {"ILLEGAL", nil, 1, 4, 0, "----"},
+	0xED: {"ILLEGAL", nil, 1, 4, 0, "----"},
+	0xEE: {"XOR", []Operand{D8}, 2, 8, 0, "Z000"},
+	0xEF: {"RST", []Operand{"28H"}, 1, 16, 0, "----"},
+	0xF0: {"LDH", []Operand{"A", IndA8}, 2, 12, 0, "----"},
+	0xF1: {"POP", []Operand{"AF"}, 1, 12, 0, "ZNHC"},
+	0xF2: {"LD", []Operand{"A", "(C)"}, 1, 8, 0, "----"},
+	0xF3: {"DI", nil, 1, 4, 0, "----"},
+	0xF4: {"ILLEGAL", nil, 1, 4, 0, "----"},
+	0xF5: {"PUSH", []Operand{"AF"}, 1, 16, 0, "----"},
+	0xF6: {"OR", []Operand{D8}, 2, 8, 0, "Z000"},
+	0xF7: {"RST", []Operand{"30H"}, 1, 16, 0, "----"},
+	0xF8: {"LD", []Operand{"HL", SPR8}, 2, 12, 0, "00HC"},
+	0xF9: {"LD", []Operand{"SP", "HL"}, 1, 8, 0, "----"},
+	0xFA: {"LD", []Operand{"A", IndA16}, 3, 16, 0, "----"},
+	0xFB: {"EI", nil, 1, 4, 0, "----"},
+	0xFC: {"ILLEGAL", nil, 1, 4, 0, "----"},
+	0xFD: {"ILLEGAL", nil, 1, 4, 0, "----"},
+	0xFE: {"CP", []Operand{D8}, 2, 8, 0, "Z1HC"},
+	0xFF: {"RST", []Operand{"38H"}, 1, 16, 0, "----"},
+}
+
+// CBPrefixOpCodes describes the CB-prefixed instructions, indexed by the
+// opcode that follows the prefix. Their length and cycles include the prefix.
+var CBPrefixOpCodes = [256]OpCode{
+	0x00: {"RLC", []Operand{"B"}, 2, 8, 0, "Z00C"},
+	0x01: {"RLC", []Operand{"C"}, 2, 8, 0, "Z00C"},
+	0x02: {"RLC", []Operand{"D"}, 2, 8, 0, "Z00C"},
+	0x03: {"RLC", []Operand{"E"}, 2, 8, 0, "Z00C"},
+	0x04: {"RLC", []Operand{"H"}, 2, 8, 0, "Z00C"},
+	0x05: {"RLC", []Operand{"L"}, 2, 8, 0, "Z00C"},
+	0x06: {"RLC", []Operand{"(HL)"}, 2, 16, 0, "Z00C"},
+	0x07: {"RLC", []Operand{"A"}, 2, 8, 0, "Z00C"},
+	0x08: {"RRC", []Operand{"B"}, 2, 8, 0, "Z00C"},
+	0x09: {"RRC", []Operand{"C"}, 2, 8, 0, "Z00C"},
+	0x0A: {"RRC", []Operand{"D"}, 2, 8, 0, "Z00C"},
+	0x0B: {"RRC", []Operand{"E"}, 2, 8, 0, "Z00C"},
+	0x0C: {"RRC", []Operand{"H"}, 2, 8, 0, "Z00C"},
+	0x0D: {"RRC", []Operand{"L"}, 2, 8, 0, "Z00C"},
+	0x0E: {"RRC", []Operand{"(HL)"}, 2, 16, 0, "Z00C"},
+	0x0F: {"RRC", []Operand{"A"}, 2, 8, 0, "Z00C"},
+	0x10: {"RL", []Operand{"B"}, 2, 8, 0, "Z00C"},
+	0x11: {"RL", []Operand{"C"}, 2, 8, 0, "Z00C"},
+	0x12: {"RL", []Operand{"D"}, 2, 8, 0, "Z00C"},
+	0x13: {"RL", []Operand{"E"}, 2, 8, 0, "Z00C"},
+	0x14: {"RL", []Operand{"H"}, 2, 8, 0, "Z00C"},
+	0x15: {"RL", []Operand{"L"}, 2, 8, 0, "Z00C"},
+	0x16: {"RL", []Operand{"(HL)"}, 2, 16, 0, "Z00C"},
+	0x17: {"RL", []Operand{"A"}, 2, 8, 0, "Z00C"},
+	0x18: {"RR", []Operand{"B"}, 2, 8, 0, "Z00C"},
+	0x19: {"RR", []Operand{"C"}, 2, 8, 0, "Z00C"},
+	0x1A: {"RR", []Operand{"D"}, 2, 8, 0, "Z00C"},
+	0x1B: {"RR", []Operand{"E"}, 2, 8, 0, "Z00C"},
+	0x1C: {"RR", []Operand{"H"}, 2, 8, 0, "Z00C"},
+	0x1D: {"RR", []Operand{"L"}, 2, 8, 0, "Z00C"},
+	0x1E: {"RR", []Operand{"(HL)"}, 2, 16, 0, "Z00C"},
+	0x1F: {"RR", []Operand{"A"}, 2, 8, 0, "Z00C"},
+	0x20: {"SLA", []Operand{"B"}, 2, 8, 0, "Z00C"},
+	0x21: {"SLA", []Operand{"C"}, 2, 8, 0, "Z00C"},
+	0x22: {"SLA", []Operand{"D"}, 2, 8, 0, "Z00C"},
+	0x23: {"SLA", []Operand{"E"}, 2, 8, 0, "Z00C"},
+	0x24: {"SLA", []Operand{"H"}, 2, 8, 0, "Z00C"},
+	0x25: {"SLA", []Operand{"L"}, 2, 8, 0, "Z00C"},
+	0x26: {"SLA", []Operand{"(HL)"}, 2, 16, 0, "Z00C"},
+	0x27: {"SLA", []Operand{"A"}, 2, 8, 0, "Z00C"},
+	0x28: {"SRA", []Operand{"B"}, 2, 8, 0, "Z00C"},
+	0x29: {"SRA", []Operand{"C"}, 2, 8, 0, "Z00C"},
+	0x2A: {"SRA", []Operand{"D"}, 2, 8, 0, "Z00C"},
+	0x2B: {"SRA", []Operand{"E"}, 2, 8, 0, "Z00C"},
+	0x2C: {"SRA", []Operand{"H"}, 2, 8, 0, "Z00C"},
+	0x2D: {"SRA", []Operand{"L"}, 2, 8, 0, "Z00C"},
+	0x2E: {"SRA", []Operand{"(HL)"}, 2, 16, 0, "Z00C"},
+	0x2F: {"SRA", []Operand{"A"}, 2, 8, 0, "Z00C"},
+	0x30: {"SWAP", []Operand{"B"}, 2, 8, 0, "Z000"},
+	0x31: {"SWAP", []Operand{"C"}, 2, 8, 0, "Z000"},
+	0x32: {"SWAP", []Operand{"D"}, 2, 8, 0, "Z000"},
+	0x33: {"SWAP", []Operand{"E"}, 2, 8, 0, "Z000"},
+	0x34: {"SWAP", []Operand{"H"}, 2, 8, 0, "Z000"},
+	0x35: {"SWAP", []Operand{"L"}, 2, 8, 0, "Z000"},
+	0x36: {"SWAP", []Operand{"(HL)"}, 2, 16, 0, "Z000"},
+	0x37: {"SWAP", []Operand{"A"}, 2, 8, 0, "Z000"},
+	0x38: {"SRL", []Operand{"B"}, 2, 8, 0, "Z00C"},
+	0x39: {"SRL", []Operand{"C"}, 2, 8, 0, "Z00C"},
+	0x3A: {"SRL", []Operand{"D"}, 2, 8, 0, "Z00C"},
+	0x3B: {"SRL", []Operand{"E"}, 2, 8, 0, "Z00C"},
+	0x3C: {"SRL", []Operand{"H"}, 2, 8, 0, "Z00C"},
+	0x3D: {"SRL", []Operand{"L"}, 2, 8, 0, "Z00C"},
+	0x3E: {"SRL", []Operand{"(HL)"}, 2, 16, 0, "Z00C"},
+	0x3F: {"SRL", []Operand{"A"}, 2, 8, 0, "Z00C"},
+	0x40: {"BIT", []Operand{"0", "B"}, 2, 8, 0, "Z01-"},
+	0x41: {"BIT", []Operand{"0", "C"}, 2, 8, 0, "Z01-"},
+	0x42: {"BIT", []Operand{"0", "D"}, 2, 8, 0, "Z01-"},
+	0x43: {"BIT", []Operand{"0", "E"}, 2, 8, 0, "Z01-"},
+	0x44: {"BIT", []Operand{"0", "H"}, 2, 8, 0, "Z01-"},
+	0x45: {"BIT", []Operand{"0", "L"}, 2, 8, 0, "Z01-"},
+	0x46: {"BIT", []Operand{"0", "(HL)"}, 2, 12, 0, "Z01-"},
+	0x47: {"BIT", []Operand{"0", "A"}, 2, 8, 0, "Z01-"},
+	0x48: {"BIT", []Operand{"1", "B"}, 2, 8, 0, "Z01-"},
+	0x49: {"BIT", []Operand{"1", "C"}, 2, 8, 0, "Z01-"},
+	0x4A: {"BIT", []Operand{"1", "D"}, 2, 8, 0, "Z01-"},
+	0x4B: {"BIT", []Operand{"1", "E"}, 2, 8, 0, "Z01-"},
+	0x4C: {"BIT", []Operand{"1", "H"}, 2, 8, 0, "Z01-"},
+	0x4D: {"BIT", []Operand{"1", "L"}, 2, 8, 0, "Z01-"},
+	0x4E: {"BIT", []Operand{"1", "(HL)"}, 2, 12, 0, "Z01-"},
+	0x4F: {"BIT", []Operand{"1", "A"}, 2, 8, 0, "Z01-"},
+	0x50: {"BIT", []Operand{"2", "B"}, 2, 8, 0, "Z01-"},
+	0x51: {"BIT", []Operand{"2", "C"}, 2, 8, 0, "Z01-"},
+	0x52: {"BIT", []Operand{"2", "D"}, 2, 8, 0, "Z01-"},
+	0x53: {"BIT", []Operand{"2", "E"}, 2, 8, 0, "Z01-"},
+	0x54: {"BIT", []Operand{"2", "H"}, 2, 8, 0, "Z01-"},
+	0x55: {"BIT", []Operand{"2", "L"}, 2, 8, 0, "Z01-"},
+	0x56: {"BIT", []Operand{"2", "(HL)"}, 2, 12, 0, "Z01-"},
+	0x57: {"BIT", []Operand{"2", "A"}, 2, 8, 0, "Z01-"},
+	0x58: {"BIT", []Operand{"3", "B"}, 2, 8, 0, "Z01-"},
+	0x59: {"BIT", []Operand{"3", "C"}, 2, 8, 0, "Z01-"},
+	0x5A: {"BIT", []Operand{"3", "D"}, 2, 8, 0, "Z01-"},
+	0x5B: {"BIT", []Operand{"3", "E"}, 2, 8, 0, "Z01-"},
+	0x5C: {"BIT", []Operand{"3", "H"}, 2, 8, 0, "Z01-"},
+	0x5D: {"BIT", []Operand{"3", "L"}, 2, 8, 0, "Z01-"},
+	0x5E: {"BIT", []Operand{"3", "(HL)"}, 2, 12, 0, "Z01-"},
+	0x5F: {"BIT", []Operand{"3", "A"}, 2, 8, 0, "Z01-"},
+	0x60: {"BIT", []Operand{"4", "B"}, 2, 8, 0, "Z01-"},
+	0x61: {"BIT", []Operand{"4", "C"}, 2, 8, 0, "Z01-"},
+	0x62: {"BIT", []Operand{"4", "D"}, 2, 8, 0, "Z01-"},
+	0x63: {"BIT", []Operand{"4", "E"}, 2, 8, 0, "Z01-"},
+	0x64: {"BIT", []Operand{"4", "H"}, 2, 8, 0, "Z01-"},
+	0x65: {"BIT", []Operand{"4", "L"}, 2, 8, 0, "Z01-"},
+	0x66: {"BIT", []Operand{"4", "(HL)"}, 2, 12, 0, "Z01-"},
+	0x67: {"BIT", []Operand{"4", "A"}, 2, 8, 0, "Z01-"},
+	0x68: {"BIT", []Operand{"5", "B"}, 2, 8, 0, "Z01-"},
+	0x69: {"BIT", []Operand{"5", "C"}, 2, 8, 0, "Z01-"},
+	0x6A: {"BIT", []Operand{"5", "D"}, 2, 8, 0, "Z01-"},
+	0x6B: {"BIT", []Operand{"5", "E"}, 2, 8, 0, "Z01-"},
+	0x6C: {"BIT", []Operand{"5", "H"}, 2, 8, 0, "Z01-"},
+	0x6D: {"BIT", []Operand{"5", "L"}, 2, 8, 0, "Z01-"},
+	0x6E: {"BIT", []Operand{"5", "(HL)"}, 2, 12, 0, "Z01-"},
+	0x6F: {"BIT", []Operand{"5", "A"}, 2, 8, 0, "Z01-"},
+	0x70: {"BIT", []Operand{"6", "B"}, 2, 8, 0, "Z01-"},
+	0x71: {"BIT", []Operand{"6", "C"}, 2, 8, 0, "Z01-"},
+	0x72: {"BIT", []Operand{"6", "D"}, 2, 8, 0, "Z01-"},
+	0x73: {"BIT", []Operand{"6", "E"}, 2, 8, 0, "Z01-"},
+	0x74: {"BIT", []Operand{"6", "H"}, 2, 8, 0, "Z01-"},
+	0x75: {"BIT", []Operand{"6", "L"}, 2, 8, 0, "Z01-"},
+	0x76: {"BIT", []Operand{"6", "(HL)"}, 2, 12, 0, "Z01-"},
+	0x77: {"BIT", []Operand{"6", "A"}, 2, 8, 0, "Z01-"},
+	0x78: {"BIT", []Operand{"7", "B"}, 2, 8, 0, "Z01-"},
+	0x79: {"BIT", []Operand{"7", "C"}, 2, 8, 0, "Z01-"},
+	0x7A: {"BIT", []Operand{"7", "D"}, 2, 8, 0, "Z01-"},
+	0x7B: {"BIT", []Operand{"7", "E"}, 2, 8, 0, "Z01-"},
+	0x7C: {"BIT", []Operand{"7", "H"}, 2, 8, 0, "Z01-"},
+	0x7D: {"BIT", []Operand{"7", "L"}, 2, 8, 0, "Z01-"},
+	0x7E: {"BIT", []Operand{"7", "(HL)"}, 2, 12, 0, "Z01-"},
+	0x7F: {"BIT", []Operand{"7", "A"}, 2, 8, 0, "Z01-"},
+	0x80: {"RES", []Operand{"0", "B"}, 2, 8, 0, "----"},
+	0x81: {"RES", []Operand{"0", "C"}, 2, 8, 0, "----"},
+	0x82: {"RES", []Operand{"0", "D"}, 2, 8, 0, "----"},
+	0x83: {"RES", []Operand{"0", "E"}, 2, 8, 0, "----"},
+	0x84: {"RES", []Operand{"0", "H"}, 2, 8, 0, "----"},
+	0x85: {"RES", []Operand{"0", "L"}, 2, 8, 0, "----"},
+	0x86: {"RES", []Operand{"0", "(HL)"}, 2, 16, 0, "----"},
+	0x87: {"RES", []Operand{"0", "A"}, 2, 8, 0, "----"},
+	0x88: {"RES", []Operand{"1", "B"}, 2, 8, 0, "----"},
+	0x89: {"RES", []Operand{"1", "C"}, 2, 8, 0, "----"},
+	0x8A: {"RES", []Operand{"1", "D"}, 2, 8, 0, "----"},
+	0x8B: {"RES", []Operand{"1", "E"}, 2, 8, 0, "----"},
+	0x8C: {"RES", []Operand{"1", "H"}, 2, 8, 0, "----"},
+	0x8D: {"RES", []Operand{"1", "L"}, 2, 8, 0, "----"},
+	0x8E: {"RES", []Operand{"1", "(HL)"}, 2, 16, 0, "----"},
+	0x8F: {"RES", []Operand{"1", "A"}, 2, 8, 0, "----"},
+	0x90: {"RES", []Operand{"2", "B"}, 2, 8, 0, "----"},
+	0x91: {"RES", []Operand{"2", "C"}, 2, 8, 0, "----"},
+	0x92: {"RES", []Operand{"2", "D"}, 2, 8, 0, "----"},
+	0x93: {"RES", []Operand{"2", "E"}, 2, 8, 0, "----"},
+	0x94: {"RES", []Operand{"2", "H"}, 2, 8, 0, "----"},
+	0x95: {"RES", []Operand{"2", "L"}, 2, 8, 0, "----"},
+	0x96: {"RES", []Operand{"2", "(HL)"}, 2, 16, 0, "----"},
+	0x97: {"RES", []Operand{"2", "A"}, 2, 8, 0, "----"},
+	0x98: {"RES", []Operand{"3", "B"}, 2, 8, 0, "----"},
+	0x99: {"RES", []Operand{"3", "C"}, 2, 8, 0, "----"},
+	0x9A: {"RES", []Operand{"3", "D"}, 2, 8, 0, "----"},
+	0x9B: {"RES", []Operand{"3", "E"}, 2, 8, 0, "----"},
+	0x9C: {"RES", []Operand{"3", "H"}, 2, 8, 0, "----"},
+	0x9D: {"RES", []Operand{"3", "L"}, 2, 8, 0, "----"},
+	0x9E: {"RES", []Operand{"3", "(HL)"}, 2, 16, 0, "----"},
+	0x9F: {"RES", []Operand{"3", "A"}, 2, 8, 0, "----"},
+	0xA0: {"RES", []Operand{"4", "B"}, 2, 8, 0, "----"},
+	0xA1: {"RES", []Operand{"4", "C"}, 2, 8, 0, "----"},
+	0xA2: {"RES", []Operand{"4", "D"}, 2, 8, 0, "----"},
+	0xA3: {"RES", []Operand{"4", "E"}, 2, 8, 0, "----"},
+	0xA4: {"RES", []Operand{"4", "H"}, 2, 8, 0, "----"},
+	0xA5: {"RES", []Operand{"4", "L"}, 2, 8, 0, "----"},
+	0xA6: {"RES", []Operand{"4", "(HL)"}, 2, 16, 0, "----"},
+	0xA7: {"RES", []Operand{"4", "A"}, 2, 8, 0, "----"},
+	0xA8: {"RES", []Operand{"5", "B"}, 2, 8, 0, "----"},
+	0xA9: {"RES", []Operand{"5", "C"}, 2, 8, 0, "----"},
+	0xAA: {"RES", []Operand{"5", "D"}, 2, 8, 0, "----"},
+	0xAB: {"RES", []Operand{"5", "E"}, 2, 8, 0, "----"},
+	0xAC: {"RES", []Operand{"5", "H"}, 2, 8, 0, "----"},
+	0xAD: {"RES", []Operand{"5", "L"}, 2, 8, 0, "----"},
+	0xAE: {"RES", []Operand{"5", "(HL)"}, 2, 16, 0, "----"},
+	0xAF: {"RES", []Operand{"5", "A"}, 2, 8, 0, "----"},
+	0xB0: {"RES", []Operand{"6", "B"}, 2, 8, 0, "----"},
+	0xB1: {"RES", []Operand{"6", "C"}, 2, 8, 0, "----"},
+	0xB2: {"RES", []Operand{"6", "D"}, 2, 8, 0, "----"},
+	0xB3: {"RES", []Operand{"6", "E"}, 2, 8, 0, "----"},
+	0xB4: {"RES", []Operand{"6", "H"}, 2, 8, 0, "----"},
+	0xB5: {"RES", []Operand{"6", "L"}, 2, 8, 0, "----"},
+	0xB6: {"RES", []Operand{"6", "(HL)"}, 2, 16, 0, "----"},
+	0xB7: {"RES", []Operand{"6", "A"}, 2, 8, 0, "----"},
+	0xB8: {"RES", []Operand{"7", "B"}, 2, 8, 0, "----"},
+	0xB9: {"RES", []Operand{"7", "C"}, 2, 8, 0, "----"},
+	0xBA: {"RES", []Operand{"7", "D"}, 2, 8, 0, "----"},
+	0xBB: {"RES", []Operand{"7", "E"}, 2, 8, 0, "----"},
+	0xBC: {"RES", []Operand{"7", "H"}, 2, 8, 0, "----"},
+	0xBD: {"RES", []Operand{"7", "L"}, 2, 8, 0, "----"},
+	0xBE: {"RES", []Operand{"7", "(HL)"}, 2, 16, 0, "----"},
+	0xBF: {"RES", []Operand{"7", "A"}, 2, 8, 0, "----"},
+	0xC0: {"SET", []Operand{"0", "B"}, 2, 8, 0, "----"},
+	0xC1: {"SET", []Operand{"0", "C"}, 2, 8, 0, "----"},
+	0xC2: {"SET", []Operand{"0", "D"}, 2, 8, 0, "----"},
+	0xC3: {"SET", []Operand{"0", "E"}, 2, 8, 0, "----"},
+	0xC4: {"SET", []Operand{"0", "H"}, 2, 8, 0, "----"},
+	0xC5: {"SET", []Operand{"0", "L"}, 2, 8, 0, "----"},
+	0xC6: {"SET", []Operand{"0", "(HL)"}, 2, 16, 0, "----"},
+	0xC7: {"SET", []Operand{"0", "A"}, 2, 8, 0, "----"},
+	0xC8: {"SET", []Operand{"1", "B"}, 2, 8, 0, "----"},
+	0xC9: {"SET", []Operand{"1", "C"}, 2, 8, 0, "----"},
+	0xCA: {"SET", []Operand{"1", "D"}, 2, 8, 0, "----"},
+	0xCB: {"SET", []Operand{"1", "E"}, 2, 8, 0, "----"},
+	0xCC: {"SET", []Operand{"1", "H"}, 2, 8, 0, "----"},
+	0xCD: {"SET", []Operand{"1", "L"}, 2, 8, 0, "----"},
+	0xCE: {"SET", []Operand{"1", "(HL)"}, 2, 16, 0, "----"},
+	0xCF: {"SET", []Operand{"1", "A"}, 2, 8, 0, "----"},
+	0xD0: {"SET", []Operand{"2", "B"}, 2, 8, 0, "----"},
+	0xD1: {"SET", []Operand{"2", "C"}, 2, 8, 0, "----"},
+	0xD2: {"SET", []Operand{"2", "D"}, 2, 8, 0, "----"},
+	0xD3: {"SET", []Operand{"2", "E"}, 2, 8, 0, "----"},
+	0xD4: {"SET", []Operand{"2", "H"}, 2, 8, 0, "----"},
+	0xD5: {"SET", []Operand{"2", "L"}, 2, 8, 0, "----"},
+	0xD6: {"SET", []Operand{"2", "(HL)"}, 2, 16, 0, "----"},
+	0xD7: {"SET", []Operand{"2", "A"}, 2, 8, 0, "----"},
+	0xD8: {"SET", []Operand{"3", "B"}, 2, 8, 0, "----"},
+	0xD9: {"SET", []Operand{"3", "C"}, 2, 8, 0, "----"},
+	0xDA: {"SET", []Operand{"3", "D"}, 2, 8, 0, "----"},
+	0xDB: {"SET", []Operand{"3", "E"}, 2, 8, 0, "----"},
+	0xDC: {"SET", []Operand{"3", "H"}, 2, 8, 0, "----"},
+	0xDD: {"SET", []Operand{"3", "L"}, 2, 8, 0, "----"},
+	0xDE: {"SET", []Operand{"3", "(HL)"}, 2, 16, 0, "----"},
+	0xDF: {"SET", []Operand{"3", "A"}, 2, 8, 0, "----"},
+	0xE0: {"SET", []Operand{"4", "B"}, 2, 8, 0, "----"},
+	0xE1: {"SET", []Operand{"4", "C"}, 2, 8, 0, "----"},
+	0xE2: {"SET", []Operand{"4", "D"}, 2, 8, 0, "----"},
+	0xE3: {"SET", []Operand{"4", "E"}, 2, 8, 0, "----"},
+	0xE4: {"SET", []Operand{"4", "H"}, 2, 8, 0, "----"},
+	0xE5: {"SET", []Operand{"4", "L"}, 2, 8, 0, "----"},
+	0xE6: {"SET", []Operand{"4", "(HL)"}, 2, 16, 0, "----"},
+	0xE7: {"SET", []Operand{"4", "A"}, 2, 8, 0, "----"},
+	0xE8: {"SET", []Operand{"5", "B"}, 2, 8, 0, "----"},
+	0xE9: {"SET", []Operand{"5", "C"}, 2, 8, 0, "----"},
+	0xEA: {"SET", []Operand{"5", "D"}, 2, 8, 0, "----"},
+	0xEB: {"SET", []Operand{"5", "E"}, 2, 8, 0, "----"},
+	0xEC: {"SET", []Operand{"5", "H"}, 2, 8, 0, "----"},
+	0xED: {"SET", []Operand{"5", "L"}, 2, 8, 0, "----"},
+	0xEE: {"SET", []Operand{"5", "(HL)"}, 2, 16, 0, "----"},
+	0xEF: {"SET", []Operand{"5", "A"}, 2, 8, 0, "----"},
+	0xF0: {"SET", []Operand{"6", "B"}, 2, 8, 0, "----"},
+	0xF1: {"SET", []Operand{"6", "C"}, 2, 8, 0, "----"},
+	0xF2: {"SET", []Operand{"6", "D"}, 2, 8, 0, "----"},
+	0xF3: {"SET", []Operand{"6", "E"}, 2, 8, 0, "----"},
+	0xF4: {"SET", []Operand{"6", "H"}, 2, 8, 0, "----"},
+	0xF5: {"SET", []Operand{"6", "L"}, 2, 8, 0, "----"},
+	0xF6: {"SET", []Operand{"6", "(HL)"}, 2, 16, 0, "----"},
+	0xF7: {"SET", []Operand{"6", "A"}, 2, 8, 0, "----"},
+	0xF8: {"SET", []Operand{"7", "B"}, 2, 8, 0, "----"},
+	0xF9: {"SET", []Operand{"7", "C"}, 2, 8, 0, "----"},
+	0xFA: {"SET", []Operand{"7", "D"}, 2, 8, 0, "----"},
+	0xFB: {"SET", []Operand{"7", "E"}, 2, 8, 0, "----"},
+	0xFC: {"SET", []Operand{"7", "H"}, 2, 8, 0, "----"},
+	0xFD: {"SET", []Operand{"7", "L"}, 2, 8, 0, "----"},
+	0xFE: {"SET", []Operand{"7", "(HL)"}, 2, 16, 0, "----"},
+	0xFF: {"SET", []Operand{"7", "A"}, 2, 8, 0, "----"},
+}
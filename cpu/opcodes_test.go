@@ -0,0 +1,210 @@
+package cpu
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lucactt/gameboy/mem"
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+// setCond sets the flags so that the given branch condition is true if taken is true.
+func setCond(regs *Regs, cond Operand, taken bool) {
+	switch cond {
+	case "NZ":
+		regs.SetZ(!taken)
+	case "Z":
+		regs.SetZ(taken)
+	case "NC":
+		regs.SetC(!taken)
+	case "C":
+		regs.SetC(taken)
+	}
+}
+
+// checkFlag verifies that a flag has been changed as described by the effect.
+func checkFlag(t *testing.T, name string, effect FlagEffect, before, after bool) {
+	t.Helper()
+
+	switch effect {
+	case FlagUnaffected:
+		if before != after {
+			t.Errorf("flag %s changed, want unaffected", name)
+		}
+	case FlagReset:
+		if after {
+			t.Errorf("flag %s set, want reset", name)
+		}
+	case FlagSet:
+		if !after {
+			t.Errorf("flag %s reset, want set", name)
+		}
+	}
+}
+
+// opCodeValues are the values given to the registers and to the memory when
+// running the instructions, chosen so that together they set and reset every flag.
+var opCodeValues = []byte{0x00, 0x01, 0x0F, 0x10, 0x80, 0x9A, 0xFF}
+
+// flagCheck records the values taken by an affected flag over the runs of an instruction.
+type flagCheck struct {
+	changed, set, reset bool
+}
+
+// add records the value of the flag before and after a run.
+func (c *flagCheck) add(before, after bool) {
+	c.changed = c.changed || before != after
+	c.set = c.set || after
+	c.reset = c.reset || !after
+}
+
+// runOpCode runs the instruction with the A and F registers set to a and f,
+// and the other registers, the immediate values and the memory they point to set to v.
+// It verifies that the length, cycles and flags match the ones described by the opcode,
+// and records the values of the affected flags in checks.
+func runOpCode(t *testing.T, instr func(*InstrSet) Instr, o OpCode, a, v, f byte, taken bool, checks *[4]flagCheck) {
+	t.Helper()
+
+	regs := NewRegs()
+	ram := mem.NewRAM(0xFFFF)
+	stateMgr := NewStateMgr()
+	set := NewInstrSet(regs, ram, stateMgr)
+
+	vv := uint16(v)<<8 | uint16(v)
+	regs.PC.Set(0x0200)
+	regs.SP.Set(0xDF00 | uint16(a))
+	regs.BC.Set(vv)
+	regs.DE.Set(vv)
+	regs.HL.Set(vv)
+	regs.AF.SetHi(a)
+	regs.AF.SetLo(f)
+
+	for _, addr := range []uint16{0x0201, 0x0202, regs.SP.HiLo(), regs.SP.HiLo() + 1, vv, 0xFF00 | uint16(v)} {
+		ram.SetByte(addr, v)
+	}
+
+	wantCycles := o.Cycles
+	if o.CyclesTaken != 0 {
+		setCond(regs, o.Operands[0], taken)
+		if taken {
+			wantCycles = o.CyclesTaken
+		}
+	}
+	before := *regs
+
+	len, cycles := instr(set)()
+	set.takeErr()
+
+	assert.Equal(t, len, o.Length)
+	assert.Equal(t, cycles, wantCycles)
+
+	flags := []struct {
+		name          string
+		effect        FlagEffect
+		before, after bool
+	}{
+		{"Z", o.Flags.Z(), before.Z(), regs.Z()},
+		{"N", o.Flags.N(), before.N(), regs.N()},
+		{"H", o.Flags.H(), before.H(), regs.H()},
+		{"C", o.Flags.C(), before.C(), regs.C()},
+	}
+	for i, fl := range flags {
+		checkFlag(t, fl.name, fl.effect, fl.before, fl.after)
+		checks[i].add(fl.before, fl.after)
+	}
+}
+
+// selfOperand checks if the instruction is an arithmetic or logic operation of A
+// with itself, whose result depends only on the previous flags. Some of its
+// affected flags can't be both set and reset.
+func selfOperand(o OpCode) bool {
+	switch o.Mnemonic {
+	case "SUB", "SBC", "XOR", "CP":
+		return o.Operands[len(o.Operands)-1] == "A"
+	}
+	return false
+}
+
+func TestOpCodes(t *testing.T) {
+	tables := []struct {
+		name    string
+		opcodes [256]OpCode
+		instr   func(*InstrSet, int) Instr
+	}{
+		{"no prefix", NoPrefixOpCodes, func(s *InstrSet, i int) Instr { return s.NoPrefix[i] }},
+		{"CB prefix", CBPrefixOpCodes, func(s *InstrSet, i int) Instr { return s.CBPrefix[i] }},
+	}
+
+	for _, tt := range tables {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, o := range tt.opcodes {
+				i, o := i, o
+				instr := func(s *InstrSet) Instr { return tt.instr(s, i) }
+
+				t.Run(fmt.Sprintf("%#02x %s", i, o), func(t *testing.T) {
+					var checks [4]flagCheck
+					for _, a := range opCodeValues {
+						for _, v := range opCodeValues {
+							for _, f := range []byte{0x00, 0xF0} {
+								runOpCode(t, instr, o, a, v, f, false, &checks)
+								if o.CyclesTaken != 0 {
+									runOpCode(t, instr, o, a, v, f, true, &checks)
+								}
+							}
+						}
+					}
+
+					effects := []FlagEffect{o.Flags.Z(), o.Flags.N(), o.Flags.H(), o.Flags.C()}
+					for j, name := range []string{"Z", "N", "H", "C"} {
+						c := checks[j]
+						if effects[j] != FlagAffected || selfOperand(o) {
+							continue
+						}
+						if !c.changed {
+							t.Errorf("flag %s never changed, want affected", name)
+						}
+						if !c.set || !c.reset {
+							t.Errorf("flag %s always %t, want affected", name, c.set)
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestOperand_ImmSize(t *testing.T) {
+	tests := []struct {
+		op   Operand
+		want int
+	}{
+		{"A", 0},
+		{"(HL+)", 0},
+		{D8, 1},
+		{IndA8, 1},
+		{SPR8, 1},
+		{A16, 2},
+		{IndA16, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.op), func(t *testing.T) {
+			assert.Equal(t, tt.op.ImmSize(), tt.want)
+		})
+	}
+}
+
+func TestOpCode_String(t *testing.T) {
+	assert.Equal(t, NoPrefixOpCodes[0x00].String(), "NOP")
+	assert.Equal(t, NoPrefixOpCodes[0x2A].String(), "LD A,(HL+)")
+	assert.Equal(t, CBPrefixOpCodes[0x7C].String(), "BIT 7,H")
+}
+
+func TestFlags(t *testing.T) {
+	f := Flags("Z01-")
+
+	assert.Equal(t, f.Z(), FlagAffected)
+	assert.Equal(t, f.N(), FlagReset)
+	assert.Equal(t, f.H(), FlagSet)
+	assert.Equal(t, f.C(), FlagUnaffected)
+}
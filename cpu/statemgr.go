@@ -14,6 +14,15 @@ const (
 type StateMgr struct {
 	current State
 	ime     bool // Interrupt Master Enable
+
+	// imeDelay is the number of instructions that must be run
+	// before IME is enabled, as EI takes effect one instruction late.
+	imeDelay int
+
+	// haltBug is set when HALT is run with IME disabled and an
+	// interrupt pending, so that the CPU fails to increment PC
+	// after fetching the next opcode.
+	haltBug bool
 }
 
 // NewStateMgr creates a new StateMgr.
@@ -22,7 +31,7 @@ func NewStateMgr() *StateMgr {
 }
 
 // State returns the current CPU state.
-func (s *StateMgr) State(n State) State {
+func (s *StateMgr) State() State {
 	return s.current
 }
 
@@ -32,11 +41,33 @@ func (s *StateMgr) SetState(n State) {
 }
 
 // InterruptsEnabled returns the current IME state.
-func (s *StateMgr) InterruptsEnabled(n State) bool {
+func (s *StateMgr) InterruptsEnabled() bool {
 	return s.ime
 }
 
 // SetIME enables or disables interrupts handling.
+// Any IME enable scheduled by ScheduleIME is cancelled.
 func (s *StateMgr) SetIME(v bool) {
 	s.ime = v
+	s.imeDelay = 0
+}
+
+// ScheduleIME enables interrupts handling after
+// the instruction that follows the current one.
+func (s *StateMgr) ScheduleIME() {
+	// The delay is 2 because step is also called
+	// after the current instruction.
+	s.imeDelay = 2
+}
+
+// step must be called after each instruction run by the CPU.
+func (s *StateMgr) step() {
+	if s.imeDelay == 0 {
+		return
+	}
+
+	s.imeDelay--
+	if s.imeDelay == 0 {
+		s.ime = true
+	}
 }
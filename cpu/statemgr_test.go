@@ -0,0 +1,40 @@
+package cpu
+
+import (
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+func TestStateMgr_ScheduleIME(t *testing.T) {
+	t.Run("enabled after two steps", func(t *testing.T) {
+		s := NewStateMgr()
+		s.SetIME(false)
+
+		s.ScheduleIME()
+		s.step()
+		assert.Equal(t, s.InterruptsEnabled(), false)
+
+		s.step()
+		assert.Equal(t, s.InterruptsEnabled(), true)
+	})
+
+	t.Run("cancelled by SetIME", func(t *testing.T) {
+		s := NewStateMgr()
+		s.SetIME(false)
+
+		s.ScheduleIME()
+		s.SetIME(false)
+		s.step()
+		s.step()
+		assert.Equal(t, s.InterruptsEnabled(), false)
+	})
+}
+
+func TestStateMgr_State(t *testing.T) {
+	s := NewStateMgr()
+	assert.Equal(t, s.State(), Running)
+
+	s.SetState(Halted)
+	assert.Equal(t, s.State(), Halted)
+}
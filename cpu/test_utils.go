@@ -203,32 +203,38 @@ func testAdd16(t *testing.T, opcode byte, getR func(*Regs) uint16, setR func(*Re
 	})
 }
 
-func testld8(t *testing.T, opcode byte, getR func(*Regs) byte, setR func(*Regs, byte)) {
+func testLd8(t *testing.T, opcode byte, getR func(*Regs) byte, setR func(*Regs, byte)) {
 	t.Helper()
 
 	regs := NewRegs()
-	ram := mem.NewRAM(1)
+	ram := mem.NewRAM(0)
 	stateMgr := NewStateMgr()
 	set := NewInstrSet(regs, ram, stateMgr)
 
-	regs.DE.Set(0x0000)
-	ram.SetByte(regs.DE.HiLo(), 0x01)
+	setR(regs, 0x11)
 
-	len, cycles := set.NoPrefix[0x1A]()
+	len, cycles := set.NoPrefix[opcode]()
 
-	assert.Equal(t, regs.AF.Hi(), byte(0x01))
+	assert.Equal(t, getR(regs), byte(0x11))
 	assert.Equal(t, len, 1)
-	assert.Equal(t, cycles, 8)
+	assert.Equal(t, cycles, 4)
+}
+
+func testALU8(t *testing.T, opcode byte, a, value byte, setR func(*Regs, byte), want byte, flags [4]bool) {
+	t.Helper()
+
 	regs := NewRegs()
 	ram := mem.NewRAM(0)
 	stateMgr := NewStateMgr()
 	set := NewInstrSet(regs, ram, stateMgr)
 
-	setR(regs, 0x0001)
+	setR(regs, value)
+	regs.AF.SetHi(a)
 
 	len, cycles := set.NoPrefix[opcode]()
 
-	assert.Equal(t, getR(regs), uint16(0x0000))
+	assert.Equal(t, regs.AF.Hi(), want)
+	assert.Equal(t, [4]bool{regs.Z(), regs.N(), regs.H(), regs.C()}, flags)
 	assert.Equal(t, len, 1)
-	assert.Equal(t, cycles, 8)
+	assert.Equal(t, cycles, 4)
 }
@@ -0,0 +1,108 @@
+package cpu
+
+import (
+	"io"
+)
+
+// TraceFilter decides if the instruction at the given PC is traced.
+type TraceFilter func(pc uint16) bool
+
+// PCRange returns a filter that traces only the instructions
+// between the start and end addresses, inclusive.
+func PCRange(start, end uint16) TraceFilter {
+	return func(pc uint16) bool {
+		return pc >= start && pc <= end
+	}
+}
+
+// ROMBank returns a filter that traces only the instructions in the
+// given ROM bank. The current function must return the bank mapped to
+// 0x4000-0x7FFF, while the instructions in 0x0000-0x3FFF are in bank 0.
+// Instructions outside the ROM are never traced.
+func ROMBank(bank int, current func() int) TraceFilter {
+	return func(pc uint16) bool {
+		switch {
+		case pc < 0x4000:
+			return bank == 0
+		case pc < 0x8000:
+			return bank == current()
+		default:
+			return false
+		}
+	}
+}
+
+// Tracer writes the state of the CPU before each instruction is run,
+// in the format used by Gameboy Doctor:
+//
+//	A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0100 PCMEM:00,C3,13,02
+//
+// Each line is written with a single call to Write, so a zerolog.Logger
+// can also be used as the writer.
+type Tracer struct {
+	w      io.Writer
+	filter TraceFilter
+	buf    []byte
+}
+
+// NewTracer creates a new tracer that writes to the given writer the
+// instructions accepted by the filter. If the filter is nil, every
+// instruction is traced.
+func NewTracer(w io.Writer, filter TraceFilter) *Tracer {
+	return &Tracer{w: w, filter: filter, buf: make([]byte, 0, 80)}
+}
+
+// trace writes the current state of the CPU.
+func (t *Tracer) trace(c *CPU) error {
+	pc := c.Regs.PC.HiLo()
+	if t.filter != nil && !t.filter(pc) {
+		return nil
+	}
+
+	b := t.buf[:0]
+	b = appendReg8(b, "A:", c.Regs.AF.Hi())
+	b = appendReg8(b, " F:", c.Regs.AF.Lo())
+	b = appendReg8(b, " B:", c.Regs.BC.Hi())
+	b = appendReg8(b, " C:", c.Regs.BC.Lo())
+	b = appendReg8(b, " D:", c.Regs.DE.Hi())
+	b = appendReg8(b, " E:", c.Regs.DE.Lo())
+	b = appendReg8(b, " H:", c.Regs.HL.Hi())
+	b = appendReg8(b, " L:", c.Regs.HL.Lo())
+	b = appendReg16(b, " SP:", c.Regs.SP.HiLo())
+	b = appendReg16(b, " PC:", pc)
+	b = append(b, " PCMEM:"...)
+	for i := uint16(0); i < 4; i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+
+		// Unreadable addresses are written as an open bus.
+		v, err := c.Mem.GetByte(pc + i)
+		if err != nil {
+			v = 0xFF
+		}
+		b = appendHex(b, v)
+	}
+	b = append(b, '\n')
+
+	t.buf = b
+	_, err := t.w.Write(b)
+	return err
+}
+
+const hexDigits = "0123456789ABCDEF"
+
+// appendHex appends a byte as two uppercase hex digits.
+func appendHex(b []byte, v byte) []byte {
+	return append(b, hexDigits[v>>4], hexDigits[v&0x0F])
+}
+
+// appendReg8 appends the name and the value of an 8 bit register.
+func appendReg8(b []byte, name string, v byte) []byte {
+	return appendHex(append(b, name...), v)
+}
+
+// appendReg16 appends the name and the value of a 16 bit register.
+func appendReg16(b []byte, name string, v uint16) []byte {
+	return appendHex(appendHex(append(b, name...), byte(v>>8)), byte(v))
+}
@@ -0,0 +1,86 @@
+package cpu
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/lucactt/gameboy/mem"
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+// failWriter is a writer that always fails.
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("test")
+}
+
+func TestTracer(t *testing.T) {
+	t.Run("format", func(t *testing.T) {
+		ram := mem.NewRAM(0xFFFF)
+		c := New(ram)
+		var buf bytes.Buffer
+		c.Tracer = NewTracer(&buf, nil)
+
+		ram.SetByte(0x0100, 0x00)
+		ram.SetByte(0x0101, 0xC3)
+		ram.SetByte(0x0102, 0x13)
+		ram.SetByte(0x0103, 0x02)
+
+		c.Tick()
+		c.Tick()
+
+		assert.Equal(t, buf.String(),
+			"A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0100 PCMEM:00,C3,13,02\n"+
+				"A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0101 PCMEM:C3,13,02,00\n")
+	})
+
+	t.Run("unreadable memory", func(t *testing.T) {
+		ram := mem.NewRAM(0x0102)
+		c := New(ram)
+		var buf bytes.Buffer
+		c.Tracer = NewTracer(&buf, nil)
+
+		c.Tick()
+
+		assert.Equal(t, buf.String(),
+			"A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0100 PCMEM:00,00,FF,FF\n")
+	})
+
+	t.Run("PC range", func(t *testing.T) {
+		ram := mem.NewRAM(0xFFFF)
+		c := New(ram)
+		var buf bytes.Buffer
+		c.Tracer = NewTracer(&buf, PCRange(0x0101, 0x0101))
+
+		c.Tick()
+		c.Tick()
+		c.Tick()
+
+		assert.Equal(t, bytes.Count(buf.Bytes(), []byte("\n")), 1)
+		assert.Equal(t, bytes.Contains(buf.Bytes(), []byte("PC:0101")), true)
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		c := New(mem.NewRAM(0xFFFF))
+		c.Tracer = NewTracer(failWriter{}, nil)
+
+		_, err := c.Tick()
+		assert.Err(t, err, true)
+	})
+}
+
+func TestROMBank(t *testing.T) {
+	current := 2
+	filter := ROMBank(2, func() int { return current })
+
+	assert.Equal(t, filter(0x0100), false)
+	assert.Equal(t, filter(0x4100), true)
+	assert.Equal(t, filter(0xC000), false)
+
+	current = 3
+	assert.Equal(t, filter(0x4100), false)
+
+	assert.Equal(t, ROMBank(0, func() int { return 1 })(0x0100), true)
+}
@@ -0,0 +1,316 @@
+// Package disasm implements a disassembler for the GameBoy CPU instructions.
+package disasm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucactt/gameboy/cpu"
+	"github.com/lucactt/gameboy/mem"
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+const (
+	// NoBank is the bank of a location that isn't in the ROM,
+	// or that is in a ROM bank that can't be known.
+	NoBank = -1
+
+	// bankSize is the size of a ROM bank.
+	bankSize = 0x4000
+
+	// cbPrefix is the opcode that selects the CB-prefixed instruction set.
+	cbPrefix byte = 0xCB
+
+	// Opcodes of LD (a16),A and LD A,(a16).
+	ldA16A byte = 0xEA
+	ldAA16 byte = 0xFA
+)
+
+// Location is the position of an instruction, as a ROM bank and
+// the address where the bank is mapped.
+type Location struct {
+	Bank int
+	Addr uint16
+}
+
+// String returns the location in the bank:address format, e.g. "01:4000".
+// If the bank is unknown, it's replaced by "--".
+func (l Location) String() string {
+	if l.Bank == NoBank {
+		return fmt.Sprintf("--:%04X", l.Addr)
+	}
+	return fmt.Sprintf("%02X:%04X", l.Bank, l.Addr)
+}
+
+// Label returns an assembly label for the location, e.g. "L01_4000".
+func (l Location) Label() string {
+	if l.Bank == NoBank {
+		return fmt.Sprintf("L_%04X", l.Addr)
+	}
+	return fmt.Sprintf("L%02X_%04X", l.Bank, l.Addr)
+}
+
+// locate returns the location of an address, assuming
+// that it's accessed by code in the given bank.
+func locate(addr uint16, bank int) Location {
+	switch {
+	case addr < bankSize:
+		return Location{0, addr}
+	case addr < 2*bankSize && bank != 0:
+		return Location{bank, addr}
+	default:
+		return Location{NoBank, addr}
+	}
+}
+
+// Instr is a decoded instruction.
+type Instr struct {
+	Loc    Location
+	Bytes  []byte
+	OpCode cpu.OpCode
+}
+
+// Len returns the length of the instruction in bytes.
+func (i Instr) Len() int {
+	return len(i.Bytes)
+}
+
+// imm returns the immediate value of the instruction.
+func (i Instr) imm() uint16 {
+	switch i.OpCode.Length - i.prefixLen() {
+	case 2:
+		return uint16(i.Bytes[1])
+	case 3:
+		return uint16(i.Bytes[2])<<8 | uint16(i.Bytes[1])
+	default:
+		return 0
+	}
+}
+
+// prefixLen returns 1 if the instruction is CB-prefixed.
+func (i Instr) prefixLen() int {
+	if i.Bytes[0] == cbPrefix {
+		return 1
+	}
+	return 0
+}
+
+// Target returns the location where the instruction jumps to,
+// if it's a jump, call or restart.
+func (i Instr) Target() (Location, bool) {
+	var addr uint16
+	switch i.OpCode.Mnemonic {
+	case "JR":
+		addr = i.Loc.Addr + uint16(i.Len()) + uint16(int8(i.imm()))
+	case "JP", "CALL":
+		if i.Len() != 3 {
+			// JP HL has no fixed target.
+			return Location{}, false
+		}
+		addr = i.imm()
+	case "RST":
+		addr = uint16(i.Bytes[0] - 0xC7)
+	default:
+		return Location{}, false
+	}
+
+	return locate(addr, i.Loc.Bank), true
+}
+
+// Ends returns true if the instruction never continues to the next one,
+// as it's an unconditional jump or return, or an illegal opcode.
+func (i Instr) Ends() bool {
+	switch i.OpCode.Mnemonic {
+	case "JP", "JR":
+		return i.OpCode.CyclesTaken == 0
+	case "RET":
+		return len(i.OpCode.Operands) == 0
+	case "RETI", "ILLEGAL":
+		return true
+	default:
+		return false
+	}
+}
+
+// shortenable checks if the instruction is a LD between A and an address
+// in 0xFF00-0xFFFF, which rgbasm can assemble with the shorter LDH encoding.
+func (i Instr) shortenable() bool {
+	return (i.Bytes[0] == ldA16A || i.Bytes[0] == ldAA16) && i.imm() >= 0xFF00
+}
+
+// String returns the instruction in RGBDS assembly, with numeric jump targets.
+func (i Instr) String() string {
+	return i.Format(nil)
+}
+
+// Format returns the instruction in RGBDS assembly. The label function is
+// used to replace jump targets with labels, and can be nil.
+func (i Instr) Format(label func(Location) (string, bool)) string {
+	o := i.OpCode
+	if o.Mnemonic == "ILLEGAL" || (o.Mnemonic == "STOP" && i.Bytes[1] != 0x00) {
+		// These can't be assembled, so they are written as data.
+		return db(i.Bytes)
+	}
+
+	mnemonic := strings.ToLower(o.Mnemonic)
+	ops := make([]string, 0, len(o.Operands))
+	for _, op := range o.Operands {
+		switch op {
+		case cpu.D8:
+			ops = append(ops, fmt.Sprintf("$%02X", i.imm()))
+		case cpu.D16:
+			ops = append(ops, fmt.Sprintf("$%04X", i.imm()))
+		case cpu.IndA8:
+			ops = append(ops, fmt.Sprintf("[$FF%02X]", i.imm()))
+		case cpu.IndA16:
+			ops = append(ops, fmt.Sprintf("[$%04X]", i.imm()))
+		case cpu.SPR8:
+			ops = append(ops, "sp "+signed(i.imm()))
+		case cpu.A16, cpu.R8:
+			if o.Mnemonic == "ADD" {
+				// ADD SP,r8
+				ops = append(ops, fmt.Sprintf("%d", int8(i.imm())))
+				continue
+			}
+			target, _ := i.Target()
+			ops = append(ops, formatTarget(target, label))
+		case "(C)":
+			// RGBDS only accepts this form with LDH.
+			mnemonic = "ldh"
+			ops = append(ops, "[c]")
+		case "00H", "08H", "10H", "18H", "20H", "28H", "30H", "38H":
+			ops = append(ops, "$"+strings.TrimSuffix(string(op), "H"))
+		default:
+			s := strings.ToLower(string(op))
+			if op.Indirect() {
+				s = "[" + strings.Trim(s, "()") + "]"
+			}
+			ops = append(ops, s)
+		}
+	}
+
+	if len(ops) == 0 {
+		return mnemonic
+	}
+	return mnemonic + " " + strings.Join(ops, ", ")
+}
+
+// formatTarget returns the label of the target, or its address if it has no label.
+func formatTarget(target Location, label func(Location) (string, bool)) string {
+	if label != nil {
+		if l, ok := label(target); ok {
+			return l
+		}
+	}
+	return fmt.Sprintf("$%04X", target.Addr)
+}
+
+// signed formats an 8 bit signed value as an addition or subtraction.
+func signed(v uint16) string {
+	n := int8(v)
+	if n < 0 {
+		return fmt.Sprintf("- %d", -int(n))
+	}
+	return fmt.Sprintf("+ %d", n)
+}
+
+// db formats bytes as an RGBDS data directive.
+func db(bytes []byte) string {
+	s := make([]string, len(bytes))
+	for i, b := range bytes {
+		s[i] = fmt.Sprintf("$%02X", b)
+	}
+	return "db " + strings.Join(s, ", ")
+}
+
+// decode decodes the instruction at the given location,
+// reading its bytes with the given function.
+func decode(loc Location, read func(off uint16) (byte, error)) (Instr, error) {
+	opCode, err := read(0)
+	if err != nil {
+		return Instr{}, errors.E("read opcode failed", err, errors.Disasm)
+	}
+
+	o := cpu.NoPrefixOpCodes[opCode]
+	if opCode == cbPrefix {
+		cbOpCode, err := read(1)
+		if err != nil {
+			return Instr{}, errors.E("read CB opcode failed", err, errors.Disasm)
+		}
+		o = cpu.CBPrefixOpCodes[cbOpCode]
+	}
+
+	bytes := make([]byte, o.Length)
+	for n := range bytes {
+		if bytes[n], err = read(uint16(n)); err != nil {
+			return Instr{}, errors.E("read operand failed", err, errors.Disasm)
+		}
+	}
+
+	return Instr{Loc: loc, Bytes: bytes, OpCode: o}, nil
+}
+
+// Decode decodes the instruction found in the memory at the given address.
+//
+// As the ROM bank mapped in the memory can't be known,
+// addresses in the switchable ROM area have no bank.
+func Decode(m mem.Mem, addr uint16) (Instr, error) {
+	return decode(locate(addr, NoBank), func(off uint16) (byte, error) {
+		return m.GetByte(addr + off)
+	})
+}
+
+// DecodeBytes decodes the instruction at the start of the given bytes,
+// which were read from the memory at the given address.
+//
+// As with Decode, addresses in the switchable ROM area have no bank.
+func DecodeBytes(b []byte, addr uint16) (Instr, error) {
+	return decode(locate(addr, NoBank), func(off uint16) (byte, error) {
+		if int(off) >= len(b) {
+			return 0, errors.E(fmt.Sprintf("offset %#x outside of bytes", off), errors.Disasm)
+		}
+		return b[off], nil
+	})
+}
+
+// DecodeROM decodes the instruction found in the ROM at the given offset.
+// If the ROM is banked, the instruction can't span two banks.
+func DecodeROM(rom []byte, off int) (Instr, error) {
+	loc := romLocation(off)
+	bankEnd := len(rom)
+	if banked(rom) {
+		bankEnd = (off/bankSize + 1) * bankSize
+	}
+
+	return decode(loc, func(n uint16) (byte, error) {
+		i := off + int(n)
+		if i >= len(rom) || i >= bankEnd {
+			return 0, errors.E(fmt.Sprintf("offset %#x outside of bank", i), errors.Disasm)
+		}
+		return rom[i], nil
+	})
+}
+
+// banked checks if the ROM has more banks than the two
+// always mapped in the memory, so it needs an MBC to switch them.
+func banked(rom []byte) bool {
+	return len(rom) > 2*bankSize
+}
+
+// romLocation returns the location of an offset in the ROM.
+func romLocation(off int) Location {
+	bank := off / bankSize
+	if bank == 0 {
+		return Location{0, uint16(off)}
+	}
+	return Location{bank, uint16(bankSize + off%bankSize)}
+}
+
+// romOffset returns the offset in the ROM of a location,
+// or -1 if it's not in the ROM.
+func romOffset(loc Location) int {
+	if loc.Bank == NoBank || (loc.Bank == 0 && loc.Addr >= bankSize) {
+		return -1
+	}
+	return loc.Bank*bankSize + int(loc.Addr%bankSize)
+}
@@ -0,0 +1,169 @@
+package disasm
+
+import (
+	"testing"
+
+	"github.com/lucactt/gameboy/mem"
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+func TestLocation_String(t *testing.T) {
+	assert.Equal(t, Location{1, 0x4000}.String(), "01:4000")
+	assert.Equal(t, Location{NoBank, 0xC000}.String(), "--:C000")
+}
+
+func TestLocation_Label(t *testing.T) {
+	assert.Equal(t, Location{1, 0x4000}.Label(), "L01_4000")
+	assert.Equal(t, Location{NoBank, 0xC000}.Label(), "L_C000")
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("valid instr", func(t *testing.T) {
+		ram := mem.NewRAM(0x0200)
+		ram.SetByte(0x0100, 0x01)
+		ram.SetByte(0x0101, 0x34)
+		ram.SetByte(0x0102, 0x12)
+
+		got, err := Decode(ram, 0x0100)
+		assert.Err(t, err, false)
+		assert.Equal(t, got.Loc, Location{0, 0x0100})
+		assert.Equal(t, got.Bytes, []byte{0x01, 0x34, 0x12})
+		assert.Equal(t, got.String(), "ld bc, $1234")
+	})
+
+	t.Run("CB prefix", func(t *testing.T) {
+		ram := mem.NewRAM(0x0002)
+		ram.SetByte(0x0000, 0xCB)
+		ram.SetByte(0x0001, 0x7C)
+
+		got, err := Decode(ram, 0x0000)
+		assert.Err(t, err, false)
+		assert.Equal(t, got.Len(), 2)
+		assert.Equal(t, got.String(), "bit 7, h")
+	})
+
+	t.Run("operand outside memory", func(t *testing.T) {
+		ram := mem.NewRAM(0x0001)
+		ram.SetByte(0x0000, 0xC3)
+
+		_, err := Decode(ram, 0x0000)
+		assert.Err(t, err, true)
+	})
+}
+
+func TestDecodeBytes(t *testing.T) {
+	t.Run("valid instr", func(t *testing.T) {
+		got, err := DecodeBytes([]byte{0xC3, 0x50, 0x01, 0x00}, 0x4100)
+		assert.Err(t, err, false)
+		assert.Equal(t, got.Loc, Location{NoBank, 0x4100})
+		assert.Equal(t, got.Bytes, []byte{0xC3, 0x50, 0x01})
+		assert.Equal(t, got.String(), "jp $0150")
+	})
+
+	t.Run("missing operand", func(t *testing.T) {
+		_, err := DecodeBytes([]byte{0xC3, 0x50}, 0x0100)
+		assert.Err(t, err, true)
+	})
+}
+
+func TestDecodeROM(t *testing.T) {
+	t.Run("switchable bank", func(t *testing.T) {
+		rom := make([]byte, 3*bankSize)
+		rom[2*bankSize+0x10] = 0x00
+
+		got, err := DecodeROM(rom, 2*bankSize+0x10)
+		assert.Err(t, err, false)
+		assert.Equal(t, got.Loc, Location{2, 0x4010})
+	})
+
+	t.Run("instr across banks", func(t *testing.T) {
+		rom := make([]byte, 4*bankSize)
+		rom[2*bankSize-1] = 0xC3
+
+		_, err := DecodeROM(rom, 2*bankSize-1)
+		assert.Err(t, err, true)
+	})
+
+	t.Run("instr across banks without MBC", func(t *testing.T) {
+		rom := make([]byte, 2*bankSize)
+		rom[bankSize-1] = 0xC3
+
+		got, err := DecodeROM(rom, bankSize-1)
+		assert.Err(t, err, false)
+		assert.Equal(t, got.Len(), 3)
+	})
+}
+
+func TestInstr_Format(t *testing.T) {
+	tests := []struct {
+		name  string
+		bytes []byte
+		want  string
+	}{
+		{"no operands", []byte{0x00}, "nop"},
+		{"indirect inc", []byte{0x2A}, "ld a, [hl+]"},
+		{"LDH", []byte{0xE0, 0x80}, "ldh [$FF80], a"},
+		{"LD (C),A", []byte{0xE2}, "ldh [c], a"},
+		{"LD (a16),SP", []byte{0x08, 0x00, 0xC0}, "ld [$C000], sp"},
+		{"JR back", []byte{0x18, 0xFE}, "jr $0100"},
+		{"JP cond", []byte{0xC2, 0x50, 0x01}, "jp nz, $0150"},
+		{"JP HL", []byte{0xE9}, "jp hl"},
+		{"RST", []byte{0xFF}, "rst $38"},
+		{"ADD SP,r8", []byte{0xE8, 0xFE}, "add sp, -2"},
+		{"LD HL,SP+r8", []byte{0xF8, 0x05}, "ld hl, sp + 5"},
+		{"STOP", []byte{0x10, 0x00}, "stop"},
+		{"illegal", []byte{0xD3}, "db $D3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ram := mem.NewRAM(0x0200)
+			for i, b := range tt.bytes {
+				ram.SetByte(0x0100+uint16(i), b)
+			}
+
+			instr, err := Decode(ram, 0x0100)
+			assert.Err(t, err, false)
+			assert.Equal(t, instr.String(), tt.want)
+		})
+	}
+
+	t.Run("label", func(t *testing.T) {
+		ram := mem.NewRAM(0x0200)
+		ram.SetByte(0x0100, 0xCD)
+		ram.SetByte(0x0101, 0x50)
+		ram.SetByte(0x0102, 0x01)
+
+		instr, _ := Decode(ram, 0x0100)
+		got := instr.Format(func(loc Location) (string, bool) { return loc.Label(), true })
+		assert.Equal(t, got, "call L00_0150")
+	})
+}
+
+func TestInstr_Target(t *testing.T) {
+	tests := []struct {
+		name  string
+		off   int
+		bytes []byte
+		want  Location
+		ok    bool
+	}{
+		{"JR in bank", bankSize + 0x10, []byte{0x18, 0x02}, Location{1, 0x4014}, true},
+		{"CALL bank 0 from bank 2", 2 * bankSize, []byte{0xCD, 0x00, 0x20}, Location{0, 0x2000}, true},
+		{"JP switchable from bank 0", 0x0000, []byte{0xC3, 0x00, 0x40}, Location{NoBank, 0x4000}, true},
+		{"JP HL", 0x0000, []byte{0xE9}, Location{}, false},
+		{"not a jump", 0x0000, []byte{0x00}, Location{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rom := make([]byte, 3*bankSize)
+			copy(rom[tt.off:], tt.bytes)
+
+			instr, _ := DecodeROM(rom, tt.off)
+			got, ok := instr.Target()
+			assert.Equal(t, ok, tt.ok)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
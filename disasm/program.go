@@ -0,0 +1,234 @@
+package disasm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// EntryPoints are the addresses where the execution of a ROM can start:
+// the entry point after the boot ROM and the interrupt vectors.
+var EntryPoints = []uint16{0x0100, 0x0040, 0x0048, 0x0050, 0x0058, 0x0060}
+
+// Program is a ROM whose code has been separated from data
+// by following the execution flow.
+type Program struct {
+	rom []byte
+
+	// code contains the instructions found in the ROM, by offset.
+	code map[int]Instr
+
+	// covered marks the bytes of the ROM that belong to an instruction.
+	covered []bool
+
+	// labels contains the offsets of the instructions that are jumped to.
+	labels map[int]bool
+}
+
+// Trace disassembles a ROM with a recursive descent, starting from the
+// given addresses in bank 0, or from EntryPoints if none are given.
+//
+// Any byte that is not reached by following jumps, calls and
+// restarts is considered data.
+func Trace(rom []byte, entries ...uint16) *Program {
+	p := &Program{
+		rom:     rom,
+		code:    make(map[int]Instr),
+		covered: make([]bool, len(rom)),
+		labels:  make(map[int]bool),
+	}
+
+	if len(entries) == 0 {
+		entries = EntryPoints
+	}
+
+	queue := make([]int, 0, len(entries))
+	for _, e := range entries {
+		queue = append(queue, int(e))
+	}
+
+	for len(queue) > 0 {
+		off := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		queue = append(queue, p.follow(off)...)
+	}
+
+	// Targets that aren't the start of an instruction can't have a label.
+	for off := range p.labels {
+		if _, ok := p.code[off]; !ok {
+			delete(p.labels, off)
+		}
+	}
+
+	return p
+}
+
+// follow decodes the instructions starting at the given offset
+// until the flow ends, and returns the offsets of the targets found.
+func (p *Program) follow(off int) []int {
+	var targets []int
+
+	for off >= 0 && off < len(p.rom) {
+		if _, ok := p.code[off]; ok || p.covered[off] {
+			// Already decoded, or in the middle of another instruction.
+			return targets
+		}
+
+		instr, err := DecodeROM(p.rom, off)
+		if err != nil || p.overlaps(off, instr.Len()) {
+			return targets
+		}
+
+		p.code[off] = instr
+		for n := 0; n < instr.Len(); n++ {
+			p.covered[off+n] = true
+		}
+
+		if target, ok := instr.Target(); ok {
+			if t := romOffset(p.resolve(target)); t >= 0 && t < len(p.rom) {
+				p.labels[t] = true
+				targets = append(targets, t)
+			}
+		}
+
+		if instr.Ends() {
+			return targets
+		}
+		off += instr.Len()
+	}
+
+	return targets
+}
+
+// overlaps checks if any of the given bytes belongs to an instruction.
+func (p *Program) overlaps(off, len int) bool {
+	for n := off; n < off+len; n++ {
+		if p.covered[n] {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve assigns a bank to a location in the switchable area jumped to from
+// bank 0, which is possible only if the ROM has no other bank than 1.
+func (p *Program) resolve(loc Location) Location {
+	if loc.Bank == NoBank && loc.Addr >= bankSize && loc.Addr < 2*bankSize && !banked(p.rom) {
+		loc.Bank = 1
+	}
+	return loc
+}
+
+// Instrs returns the instructions found in the ROM, sorted by offset.
+func (p *Program) Instrs() []Instr {
+	offs := make([]int, 0, len(p.code))
+	for off := range p.code {
+		offs = append(offs, off)
+	}
+	sort.Ints(offs)
+
+	res := make([]Instr, len(offs))
+	for i, off := range offs {
+		res[i] = p.code[off]
+	}
+	return res
+}
+
+// IsCode returns true if the byte at the given ROM offset belongs to an instruction.
+func (p *Program) IsCode(off int) bool {
+	return off >= 0 && off < len(p.covered) && p.covered[off]
+}
+
+// label returns the label of a location, if it's the target of a jump.
+func (p *Program) label(loc Location) (string, bool) {
+	loc = p.resolve(loc)
+	if off := romOffset(loc); off >= 0 && p.labels[off] {
+		return loc.Label(), true
+	}
+	return "", false
+}
+
+// WriteTo writes the program as RGBDS assembly, which
+// can be assembled back into the original ROM.
+func (p *Program) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	cw := &countWriter{w: bw}
+
+	for off := 0; off < len(p.rom); {
+		if off%bankSize == 0 {
+			writeSection(cw, off/bankSize)
+		}
+
+		if p.labels[off] {
+			fmt.Fprintf(cw, "%s:\n", romLocation(off).Label())
+		}
+
+		if instr, ok := p.code[off]; ok {
+			p.writeInstr(cw, off, instr)
+			off += instr.Len()
+			continue
+		}
+
+		// Data runs until the next instruction or bank, with at most 16 bytes per line.
+		end := off + 1
+		for end < len(p.rom) && end-off < 16 && !p.covered[end] && end%bankSize != 0 {
+			end++
+		}
+		fmt.Fprintf(cw, "\t%s\n", db(p.rom[off:end]))
+		off = end
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, bw.Flush()
+}
+
+// writeInstr writes the instruction at the given offset. The instructions
+// that can't be assembled back into the same bytes are written as data,
+// followed by the instruction as a comment.
+func (p *Program) writeInstr(w io.Writer, off int, instr Instr) {
+	text := instr.Format(p.label)
+
+	// An instruction that crosses the end of bank 0, which is allowed
+	// only if the ROM isn't banked, is split between the two sections.
+	if split := (off/bankSize + 1) * bankSize; off+instr.Len() > split {
+		fmt.Fprintf(w, "\t%-24s ; %s %s\n", db(p.rom[off:split]), instr.Loc, text)
+		writeSection(w, split/bankSize)
+		fmt.Fprintf(w, "\t%s\n", db(p.rom[split:off+instr.Len()]))
+		return
+	}
+
+	if instr.shortenable() {
+		fmt.Fprintf(w, "\t%-24s ; %s %s\n", db(instr.Bytes), instr.Loc, text)
+		return
+	}
+	fmt.Fprintf(w, "\t%-24s ; %s\n", text, instr.Loc)
+}
+
+// writeSection writes the RGBDS section directive for a ROM bank.
+func writeSection(w io.Writer, bank int) {
+	if bank == 0 {
+		fmt.Fprintf(w, "SECTION \"ROM Bank $00\", ROM0[$0000]\n\n")
+		return
+	}
+	fmt.Fprintf(w, "\nSECTION \"ROM Bank $%02X\", ROMX[$4000], BANK[$%02X]\n\n", bank, bank)
+}
+
+// countWriter counts the bytes written, and keeps the first error.
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countWriter) Write(b []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
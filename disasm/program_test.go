@@ -0,0 +1,117 @@
+package disasm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+// testROM returns a 32KB ROM with a small program at the entry point,
+// and with the interrupt vectors filled with illegal opcodes.
+func testROM() []byte {
+	rom := make([]byte, 2*bankSize)
+	for _, v := range EntryPoints {
+		rom[v] = 0xD3
+	}
+
+	copy(rom[0x0100:], []byte{
+		0x00,             // nop
+		0xC3, 0x50, 0x01, // jp $0150
+	})
+	copy(rom[0x0150:], []byte{
+		0xCD, 0x00, 0x40, // call $4000
+		0x20, 0xFB, // jr nz, $0150
+		0x18, 0xFE, // jr $0155
+	})
+	copy(rom[0x4000:], []byte{
+		0x3E, 0x11, // ld a, $11
+		0xC9, // ret
+	})
+	return rom
+}
+
+func TestTrace(t *testing.T) {
+	p := Trace(testROM())
+
+	var got []string
+	for _, instr := range p.Instrs() {
+		got = append(got, instr.Loc.String()+" "+instr.String())
+	}
+
+	assert.Equal(t, got, []string{
+		"00:0040 db $D3",
+		"00:0048 db $D3",
+		"00:0050 db $D3",
+		"00:0058 db $D3",
+		"00:0060 db $D3",
+		"00:0100 nop",
+		"00:0101 jp $0150",
+		"00:0150 call $4000",
+		"00:0153 jr nz, $0150",
+		"00:0155 jr $0155",
+		"01:4000 ld a, $11",
+		"01:4002 ret",
+	})
+
+	assert.Equal(t, p.IsCode(0x0104), false)
+	assert.Equal(t, p.IsCode(0x0152), true)
+}
+
+func TestProgram_WriteTo(t *testing.T) {
+	rom := testROM()
+	p := Trace(rom)
+
+	var buf bytes.Buffer
+	n, err := p.WriteTo(&buf)
+	assert.Err(t, err, false)
+	assert.Equal(t, n, int64(buf.Len()))
+
+	out := buf.String()
+	for _, want := range []string{
+		"SECTION \"ROM Bank $00\", ROM0[$0000]\n",
+		"SECTION \"ROM Bank $01\", ROMX[$4000], BANK[$01]\n",
+		"L00_0150:\n\tcall L01_4000",
+		"\tjr nz, L00_0150",
+		"L00_0155:\n\tjr L00_0155",
+		"L01_4000:\n\tld a, $11",
+		"\tjp L00_0150              ; 00:0101\n",
+		"\tdb $00, $00, $00, $00, $00, $00, $00, $00, $00, $00, $00, $00, $00, $00, $00, $00\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output doesn't contain %q", want)
+		}
+	}
+}
+
+func TestProgram_WriteTo_data(t *testing.T) {
+	rom := make([]byte, 2*bankSize)
+	copy(rom[0x0100:], []byte{
+		0xEA, 0x44, 0xFF, // ld [$FF44], a
+		0xFA, 0x00, 0xC0, // ld a, [$C000]
+		0xC3, 0xFE, 0x3F, // jp $3FFE
+	})
+	copy(rom[0x3FFE:], []byte{
+		0xC3, 0x00, 0x01, // jp $0100
+	})
+	p := Trace(rom, 0x0100)
+
+	var buf bytes.Buffer
+	_, err := p.WriteTo(&buf)
+	assert.Err(t, err, false)
+
+	out := buf.String()
+	for _, want := range []string{
+		"\tdb $EA, $44, $FF         ; 00:0100 ld [$FF44], a\n",
+		"\tld a, [$C000]            ; 00:0103\n",
+		"\tdb $C3, $00              ; 00:3FFE jp L00_0100\n" +
+			"\nSECTION \"ROM Bank $01\", ROMX[$4000], BANK[$01]\n\n" +
+			"\tdb $01\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output doesn't contain %q", want)
+		}
+	}
+	assert.Equal(t, strings.Count(out, "SECTION"), 2)
+}
@@ -1,13 +1,140 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/lucactt/gameboy/cart"
+	"github.com/lucactt/gameboy/cpu"
+	"github.com/lucactt/gameboy/system"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
+// frameCycles is the number of CPU cycles in a frame.
+const frameCycles = 70224
+
+// saveQuiet is the time without cartridge RAM writes
+// after which the save file is written.
+const saveQuiet = 2 * time.Second
+
+// validations maps the values of the validate flag to the cartridge validation modes.
+var validations = map[string]cart.Validation{
+	"reject": cart.ValidateReject,
+	"warn":   cart.ValidateWarn,
+	"ignore": cart.ValidateIgnore,
+}
+
 func main() {
+	romPath := flag.String("rom", "", "path of the ROM to run")
+	tracePath := flag.String("trace", "", "write an instruction trace to the given file, or to the log if \"log\"")
+	traceFrom := flag.Uint("trace-from", 0x0000, "trace only instructions at or after this PC")
+	traceTo := flag.Uint("trace-to", 0xFFFF, "trace only instructions at or before this PC")
+	traceBank := flag.Int("trace-bank", -1, "trace only instructions in this ROM bank")
+	entry := flag.String("entry", "", "name of the ROM to load from an archive containing several ROMs")
+	validate := flag.String("validate", "warn", "how to handle an invalid cartridge header: \"reject\", \"warn\" or \"ignore\"")
+	flag.Parse()
+
 	output := zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: "15:04"}
 	log.Logger = zerolog.New(output).With().Timestamp().Logger()
+
+	if *romPath == "" {
+		log.Fatal().Msg("no ROM given")
+	}
+
+	validation, ok := validations[*validate]
+	if !ok {
+		log.Fatal().Str("validate", *validate).Msg("invalid validation mode")
+	}
+
+	gb, err := system.Open(*romPath, ioutil.Discard, cart.WithValidation(validation), cart.WithEntry(chooseEntry(*entry)))
+	if err != nil {
+		log.Fatal().Err(err).Msg("load ROM failed")
+	}
+	log.Info().Str("title", gb.Cart.Title()).Str("type", gb.Cart.Header().Type.String()).Msg("cartridge loaded")
+
+	if *tracePath != "" {
+		var w io.Writer = log.Logger
+		if *tracePath != "log" {
+			f, err := os.Create(*tracePath)
+			if err != nil {
+				log.Fatal().Err(err).Msg("create trace file failed")
+			}
+			defer f.Close()
+			w = f
+		}
+
+		filter := cpu.PCRange(uint16(*traceFrom), uint16(*traceTo))
+		if *traceBank >= 0 {
+			inRange, inBank := filter, cpu.ROMBank(*traceBank, gb.Cart.ROMBank)
+			filter = func(pc uint16) bool { return inRange(pc) && inBank(pc) }
+		}
+		gb.CPU.Tracer = cpu.NewTracer(w, filter)
+	}
+
+	saver := cart.NewSaver(gb.Cart, cart.SavePath(*romPath), saveQuiet)
+	if err := saver.Load(); err != nil {
+		log.Fatal().Err(err).Msg("load save failed")
+	}
+
+	run(gb.CPU, saver)
+
+	if err := saver.Save(); err != nil {
+		log.Error().Err(err).Msg("write save failed")
+	}
+}
+
+// chooseEntry returns a function which chooses the archive entry with the given name,
+// or the first entry if the name is empty.
+func chooseEntry(name string) cart.EntryChooser {
+	return func(names []string) (int, error) {
+		if name == "" {
+			log.Warn().Strs("entries", names).Msg("archive contains several ROMs, loading the first")
+			return 0, nil
+		}
+
+		for i, n := range names {
+			if n == name {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("no entry named %q in %v", name, names)
+	}
+}
+
+// run ticks the CPU until it stops or the process is interrupted.
+// Once per frame, the saver is polled to persist the cartridge RAM.
+func run(gb *cpu.CPU, saver *cart.Saver) {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	for cycles := 0; ; {
+		n, err := gb.Tick()
+		if err != nil {
+			log.Error().Err(err).Msg("CPU stopped")
+			return
+		}
+
+		if cycles += n; cycles < frameCycles {
+			continue
+		}
+		cycles -= frameCycles
+
+		if err := saver.Poll(); err != nil {
+			log.Error().Err(err).Msg("write save failed")
+		}
+
+		select {
+		case <-interrupt:
+			return
+		default:
+		}
+	}
 }
@@ -0,0 +1,130 @@
+package mem
+
+import (
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// Start addresses of the regions of the DMG memory map.
+const (
+	ROMStart      uint16 = 0x0000
+	VRAMStart     uint16 = 0x8000
+	ExtRAMStart   uint16 = 0xA000
+	WRAMStart     uint16 = 0xC000
+	EchoStart     uint16 = 0xE000
+	OAMStart      uint16 = 0xFE00
+	UnusableStart uint16 = 0xFEA0
+	IOStart       uint16 = 0xFF00
+	HRAMStart     uint16 = 0xFF80
+	IEAddr        uint16 = 0xFFFF
+)
+
+// Sizes of the regions of the DMG memory map.
+const (
+	VRAMSize     uint16 = 0x2000
+	ExtRAMSize   uint16 = 0x2000
+	WRAMSize     uint16 = 0x2000
+	EchoSize     uint16 = 0x1E00
+	OAMSize      uint16 = 0x00A0
+	UnusableSize uint16 = 0x0060
+	IOSize       uint16 = 0x0080
+	HRAMSize     uint16 = 0x007F
+)
+
+// openBus is the value read from addresses not
+// connected to any device.
+const openBus byte = 0xFF
+
+// Model is a GameBoy hardware model.
+type Model int
+
+// Models that change the behaviour of the memory map.
+const (
+	// DMG is the original GameBoy.
+	DMG Model = iota
+	// CGB is the GameBoy Color.
+	CGB
+)
+
+// unusable returns the memory of the unusable area, which reads as 0x00
+// on the DMG and as 0xFF on the CGB. The revision dependent patterns
+// of the later CGB models are not emulated.
+func (m Model) unusable() Mem {
+	if m == CGB {
+		return NewFill(UnusableSize, openBus)
+	}
+	return NewNull(UnusableSize)
+}
+
+// namedMem is a memory to add to an MMU.
+type namedMem struct {
+	name  string
+	start uint16
+	mem   Mem
+}
+
+// MapDMG adds to the MMU the memories of the DMG memory map:
+//
+//	0x0000-0x7FFF  cartridge ROM
+//	0x8000-0x9FFF  VRAM
+//	0xA000-0xBFFF  cartridge RAM
+//	0xC000-0xDFFF  WRAM
+//	0xE000-0xFDFF  echo of 0xC000-0xDDFF
+//	0xFE00-0xFE9F  OAM
+//	0xFEA0-0xFEFF  unusable, reads 0x00 on the DMG and 0xFF on the CGB
+//	0xFF00-0xFF7F  I/O registers
+//	0xFF80-0xFFFE  HRAM
+//	0xFFFF         IE register
+//
+// The cartridge must accept the ROM and RAM addresses. If it has no RAM,
+// the RAM addresses read as 0xFF. The I/O registers are relative to 0xFF00,
+// and the addresses not accepted by io read as 0xFF and ignore writes.
+// If io is nil, every I/O register reads as 0xFF.
+// The IE register is at address 0 of ie.
+//
+// The model selects the value read from the unusable area.
+//
+// It returns an error if cart or ie are nil, or if the MMU rejects a memory.
+func MapDMG(mmu *MMU, model Model, cart, io, ie Mem) error {
+	if cart == nil {
+		return errors.E("map dmg memory failed: no cartridge", errors.Mem)
+	}
+	if ie == nil {
+		return errors.E("map dmg memory failed: no IE register", errors.Mem)
+	}
+
+	wram := NewRAM(WRAMSize)
+
+	// The I/O page is filled with 0xFF behind the registers.
+	ioPage := &MMU{}
+	if io != nil {
+		if err := ioPage.AddNamedMem("I/O", 0x0000, io); err != nil {
+			return errors.E("map dmg memory failed", err, errors.Mem)
+		}
+	}
+	if err := ioPage.AddNamedMem("unused I/O", 0x0000, NewFill(IOSize, openBus)); err != nil {
+		return errors.E("map dmg memory failed", err, errors.Mem)
+	}
+
+	mems := []namedMem{
+		{"cartridge", ROMStart, cart},
+		{"VRAM", VRAMStart, NewRAM(VRAMSize)},
+		{"WRAM", WRAMStart, wram},
+		{"echo RAM", EchoStart, NewMirror(wram, EchoSize)},
+		{"OAM", OAMStart, NewRAM(OAMSize)},
+		{"unusable", UnusableStart, model.unusable()},
+		{"I/O", IOStart, ioPage},
+		{"HRAM", HRAMStart, NewRAM(HRAMSize)},
+		{"IE", IEAddr, ie},
+	}
+
+	if !cart.Accepts(ExtRAMStart) {
+		mems = append(mems, namedMem{"no cartridge RAM", ExtRAMStart, NewFill(ExtRAMSize, openBus)})
+	}
+
+	for _, m := range mems {
+		if err := mmu.AddNamedMem(m.name, m.start, m.mem); err != nil {
+			return errors.E("map dmg memory failed", err, errors.Mem)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package mem
+
+import (
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+// testCart is a cartridge with a ROM at 0x0000-0x7FFF,
+// and optionally a RAM at 0xA000-0xBFFF.
+type testCart struct {
+	rom *RAM
+	ram *RAM
+}
+
+func newTestCart(hasRAM bool) *testCart {
+	c := &testCart{rom: NewRAM(0x8000)}
+	if hasRAM {
+		c.ram = NewRAM(ExtRAMSize)
+	}
+	return c
+}
+
+func (c *testCart) GetByte(addr uint16) (byte, error) {
+	if addr >= ExtRAMStart {
+		return c.ram.GetByte(addr - ExtRAMStart)
+	}
+	return c.rom.GetByte(addr)
+}
+
+func (c *testCart) SetByte(addr uint16, value byte) error {
+	if addr >= ExtRAMStart {
+		return c.ram.SetByte(addr-ExtRAMStart, value)
+	}
+	return c.rom.SetByte(addr, value)
+}
+
+func (c *testCart) Accepts(addr uint16) bool {
+	if addr >= ExtRAMStart {
+		return c.ram != nil && c.ram.Accepts(addr-ExtRAMStart)
+	}
+	return c.rom.Accepts(addr)
+}
+
+func newDMG(t *testing.T, hasRAM bool) *MMU {
+	t.Helper()
+
+	io := &MMU{}
+	io.AddMem(0x0F, NewRAM(1))
+
+	mmu := &MMU{Overlap: OverlapReject}
+	err := MapDMG(mmu, DMG, newTestCart(hasRAM), io, NewRAM(1))
+	assert.Err(t, err, false)
+	return mmu
+}
+
+func TestMapDMG(t *testing.T) {
+	mmu := newDMG(t, true)
+
+	t.Run("whole address space", func(t *testing.T) {
+		for addr := 0; addr <= 0xFFFF; addr++ {
+			if !mmu.Accepts(uint16(addr)) {
+				t.Fatalf("addr %04X not mapped", addr)
+			}
+		}
+	})
+
+	tests := []struct {
+		name string
+		addr uint16
+		want string
+	}{
+		{"ROM", 0x0150, "cartridge"},
+		{"VRAM", 0x9FFF, "VRAM"},
+		{"cartridge RAM", 0xA000, "cartridge"},
+		{"WRAM", 0xC000, "WRAM"},
+		{"echo RAM", 0xFDFF, "echo RAM"},
+		{"OAM", 0xFE9F, "OAM"},
+		{"unusable", 0xFEA0, "unusable"},
+		{"I/O", 0xFF0F, "I/O"},
+		{"HRAM", 0xFF80, "HRAM"},
+		{"IE", 0xFFFF, "IE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := mmu.RegionAt(tt.addr)
+			assert.Equal(t, ok, true)
+			assert.Equal(t, got.Name, tt.want)
+		})
+	}
+
+	t.Run("unused I/O reads 0xFF", func(t *testing.T) {
+		err := mmu.SetByte(0xFF4D, 0x11)
+		assert.Err(t, err, false)
+
+		got, _ := mmu.GetByte(0xFF4D)
+		assert.Equal(t, got, byte(0xFF))
+	})
+
+	t.Run("I/O register", func(t *testing.T) {
+		mmu.SetByte(0xFF0F, 0x11)
+
+		got, _ := mmu.GetByte(0xFF0F)
+		assert.Equal(t, got, byte(0x11))
+	})
+}
+
+func TestMapDMG_Echo(t *testing.T) {
+	tests := []struct {
+		name     string
+		setAddr  uint16
+		readAddr uint16
+	}{
+		{"write WRAM, read echo", 0xC000, 0xE000},
+		{"write echo, read WRAM", 0xE123, 0xC123},
+		{"last echo byte", 0xFDFF, 0xDDFF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mmu := newDMG(t, true)
+
+			err := mmu.SetByte(tt.setAddr, 0x11)
+			assert.Err(t, err, false)
+
+			got, err := mmu.GetByte(tt.readAddr)
+			assert.Err(t, err, false)
+			assert.Equal(t, got, byte(0x11))
+		})
+	}
+
+	t.Run("end of WRAM not echoed", func(t *testing.T) {
+		mmu := newDMG(t, true)
+		mmu.SetByte(0xDE00, 0x11)
+
+		got, _ := mmu.GetByte(0xFE00)
+		assert.Equal(t, got, byte(0x00))
+	})
+}
+
+func TestMapDMG_Unusable(t *testing.T) {
+	tests := []struct {
+		name  string
+		model Model
+		want  byte
+	}{
+		{"DMG", DMG, 0x00},
+		{"CGB", CGB, 0xFF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mmu := &MMU{Overlap: OverlapReject}
+			err := MapDMG(mmu, tt.model, newTestCart(true), nil, NewRAM(1))
+			assert.Err(t, err, false)
+
+			err = mmu.SetByte(0xFEFF, 0x11)
+			assert.Err(t, err, false)
+
+			for _, addr := range []uint16{0xFEA0, 0xFEFF} {
+				got, _ := mmu.GetByte(addr)
+				assert.Equal(t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapDMG_NoCartRAM(t *testing.T) {
+	mmu := newDMG(t, false)
+
+	err := mmu.SetByte(0xA000, 0x11)
+	assert.Err(t, err, false)
+
+	got, _ := mmu.GetByte(0xBFFF)
+	assert.Equal(t, got, byte(0xFF))
+}
+
+func TestMapDMG_Overlap(t *testing.T) {
+	mmu := &MMU{Overlap: OverlapReject}
+	mmu.AddMem(0xC000, NewRAM(1))
+
+	err := MapDMG(mmu, DMG, newTestCart(true), nil, NewRAM(1))
+	assert.Err(t, err, true)
+}
+
+func TestMapDMG_Nil(t *testing.T) {
+	t.Run("no IE", func(t *testing.T) {
+		err := MapDMG(&MMU{}, DMG, newTestCart(true), nil, nil)
+		assert.Err(t, err, true)
+	})
+
+	t.Run("no cartridge", func(t *testing.T) {
+		err := MapDMG(&MMU{}, DMG, nil, nil, NewRAM(1))
+		assert.Err(t, err, true)
+	})
+}
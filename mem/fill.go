@@ -0,0 +1,43 @@
+package mem
+
+import (
+	"fmt"
+
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// Fill is a memory where writes
+// have no effect and reads always return the same value.
+type Fill struct {
+	len   uint16
+	value byte
+}
+
+// NewFill creates a new Fill with addresses from
+// 0x0000 to the given length, which reads as the given value.
+func NewFill(len uint16, value byte) *Fill {
+	return &Fill{len, value}
+}
+
+// GetByte returns the fill value if the address
+// is inside the memory. Otherwise it returns an error.
+func (f *Fill) GetByte(addr uint16) (byte, error) {
+	if !f.Accepts(addr) {
+		return 0, errors.E(fmt.Sprintf("address %v outside of space", addr), errors.Mem)
+	}
+	return f.value, nil
+}
+
+// SetByte has no effect if the address
+// is inside the memory. Otherwise it returns an error.
+func (f *Fill) SetByte(addr uint16, value byte) error {
+	if !f.Accepts(addr) {
+		return errors.E(fmt.Sprintf("address %v outside of space", addr), errors.Mem)
+	}
+	return nil
+}
+
+// Accepts checks if an address is included in the memory.
+func (f *Fill) Accepts(addr uint16) bool {
+	return addr < f.len
+}
@@ -0,0 +1,44 @@
+package mem
+
+import (
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+func TestFill_GetByte(t *testing.T) {
+	t.Run("inside space", func(t *testing.T) {
+		mem := NewFill(0x1000, 0xFF)
+
+		got, err := mem.GetByte(0x0001)
+
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0xFF))
+	})
+
+	t.Run("outside space", func(t *testing.T) {
+		mem := NewFill(0x1000, 0xFF)
+
+		_, err := mem.GetByte(0x1001)
+		assert.Err(t, err, true)
+	})
+}
+
+func TestFill_SetByte(t *testing.T) {
+	t.Run("inside space", func(t *testing.T) {
+		mem := NewFill(0x1000, 0xFF)
+
+		err := mem.SetByte(0x0001, 0x11)
+		assert.Err(t, err, false)
+
+		got, _ := mem.GetByte(0x0001)
+		assert.Equal(t, got, byte(0xFF))
+	})
+
+	t.Run("outside space", func(t *testing.T) {
+		mem := NewFill(0x1000, 0xFF)
+
+		err := mem.SetByte(0x1001, 0x11)
+		assert.Err(t, err, true)
+	})
+}
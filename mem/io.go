@@ -0,0 +1,139 @@
+package mem
+
+import (
+	"fmt"
+
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// Reg describes the behaviour of an I/O register.
+//
+// The zero value is a register whose bits can all be read and written.
+type Reg struct {
+	// Name identifies the register, for example "DIV".
+	Name string
+
+	// Unused are the bits that always read as 1 and ignore writes.
+	Unused byte
+	// ReadOnly are the bits that ignore writes.
+	ReadOnly byte
+	// WriteOnly are the bits that read as 1, but can be written.
+	WriteOnly byte
+
+	// Read returns the value of the register. If nil,
+	// the last value stored in the register is returned.
+	Read func() byte
+	// Write is called after a write, with the new value of the register.
+	// It can be used to trigger side effects, such as resetting
+	// a counter or starting a transfer.
+	Write func(value byte)
+}
+
+// ioReg is a register of an IO, with its stored value.
+type ioReg struct {
+	Reg
+	value byte
+}
+
+// IO is a memory made of I/O registers, each with its own
+// read and write behaviour. The addresses without a register
+// read as 0xFF and ignore writes.
+//
+// It implements the Mem interface.
+type IO struct {
+	regs []*ioReg
+}
+
+// NewIO creates a new IO with addresses from
+// 0x0000 to the given length, and no registers.
+func NewIO(len uint16) *IO {
+	return &IO{make([]*ioReg, len)}
+}
+
+// Register adds a register at the given address.
+// It returns an error if the address is outside the memory
+// or already contains a register.
+func (io *IO) Register(addr uint16, r Reg) error {
+	if !io.Accepts(addr) {
+		return errors.E(fmt.Sprintf("register %s: address %v outside of space", r.Name, addr), errors.Mem)
+	}
+
+	if old := io.regs[addr]; old != nil {
+		return errors.E(fmt.Sprintf("register %s: address %v already used by %s", r.Name, addr, old.Name), errors.Mem)
+	}
+
+	io.regs[addr] = &ioReg{Reg: r}
+	return nil
+}
+
+// Value returns the value stored in the register at the given address,
+// ignoring the masks and the read function.
+// It returns 0xFF if there is no register at the address.
+func (io *IO) Value(addr uint16) byte {
+	if !io.Accepts(addr) || io.regs[addr] == nil {
+		return 0xFF
+	}
+	return io.regs[addr].value
+}
+
+// SetValue stores a value in the register at the given address,
+// ignoring the masks and the write function.
+// It is used by the devices to update their registers.
+func (io *IO) SetValue(addr uint16, value byte) {
+	if io.Accepts(addr) && io.regs[addr] != nil {
+		io.regs[addr].value = value
+	}
+}
+
+// GetByte returns the value of the register at the given address,
+// with the unused and write only bits set to 1.
+// If the address is outside the memory, it returns an error.
+func (io *IO) GetByte(addr uint16) (byte, error) {
+	if !io.Accepts(addr) {
+		return 0, errors.E(fmt.Sprintf("address %v outside of space", addr), errors.Mem)
+	}
+
+	r := io.regs[addr]
+	if r == nil {
+		return 0xFF, nil
+	}
+
+	value := r.value
+	if r.Read != nil {
+		value = r.Read()
+	}
+	return value | r.Unused | r.WriteOnly, nil
+}
+
+// SetByte writes the writable bits of the register at the given
+// address, and then calls its write function with the new value.
+// If the address is outside the memory, it returns an error.
+func (io *IO) SetByte(addr uint16, value byte) error {
+	if !io.Accepts(addr) {
+		return errors.E(fmt.Sprintf("address %v outside of space", addr), errors.Mem)
+	}
+
+	r := io.regs[addr]
+	if r == nil {
+		return nil
+	}
+
+	// The bits that can't be written keep their current value,
+	// which is returned by the read function if there is one.
+	current := r.value
+	if r.Read != nil {
+		current = r.Read()
+	}
+
+	mask := ^(r.Unused | r.ReadOnly)
+	r.value = current&^mask | value&mask
+	if r.Write != nil {
+		r.Write(r.value)
+	}
+	return nil
+}
+
+// Accepts checks if an address is included in the memory.
+func (io *IO) Accepts(addr uint16) bool {
+	return int(addr) < len(io.regs)
+}
@@ -0,0 +1,147 @@
+package mem
+
+import (
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+func TestIO_Register(t *testing.T) {
+	io := NewIO(0x10)
+
+	err := io.Register(0x01, Reg{Name: "A"})
+	assert.Err(t, err, false)
+
+	t.Run("address used", func(t *testing.T) {
+		err := io.Register(0x01, Reg{Name: "B"})
+		assert.Err(t, err, true)
+	})
+
+	t.Run("outside space", func(t *testing.T) {
+		err := io.Register(0x10, Reg{Name: "C"})
+		assert.Err(t, err, true)
+	})
+}
+
+func TestIO_GetByte(t *testing.T) {
+	tests := []struct {
+		name  string
+		reg   Reg
+		value byte
+		want  byte
+	}{
+		{"plain", Reg{}, 0x5A, 0x5A},
+		{"unused bits", Reg{Unused: 0xE0}, 0x05, 0xE5},
+		{"write only bits", Reg{WriteOnly: 0x0F}, 0x50, 0x5F},
+		{"read only bits", Reg{ReadOnly: 0x0F}, 0x5A, 0x5A},
+		{"read function", Reg{Unused: 0x80, Read: func() byte { return 0x12 }}, 0x5A, 0x92},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			io := NewIO(0x10)
+			io.Register(0x01, tt.reg)
+			io.SetValue(0x01, tt.value)
+
+			got, err := io.GetByte(0x01)
+			assert.Err(t, err, false)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+
+	t.Run("no register", func(t *testing.T) {
+		got, err := NewIO(0x10).GetByte(0x02)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0xFF))
+	})
+
+	t.Run("outside space", func(t *testing.T) {
+		_, err := NewIO(0x10).GetByte(0x10)
+		assert.Err(t, err, true)
+	})
+}
+
+func TestIO_SetByte(t *testing.T) {
+	tests := []struct {
+		name  string
+		reg   Reg
+		value byte
+		want  byte
+	}{
+		{"plain", Reg{}, 0x5A, 0x5A},
+		{"unused bits", Reg{Unused: 0xE0}, 0xFF, 0x1F},
+		{"read only bits", Reg{ReadOnly: 0x07}, 0xFF, 0xF8},
+		{"write only bits", Reg{WriteOnly: 0x0F}, 0xFF, 0xFF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			io := NewIO(0x10)
+			io.Register(0x01, tt.reg)
+
+			err := io.SetByte(0x01, tt.value)
+			assert.Err(t, err, false)
+			assert.Equal(t, io.Value(0x01), tt.want)
+		})
+	}
+
+	t.Run("write function", func(t *testing.T) {
+		var got []byte
+		io := NewIO(0x10)
+		io.Register(0x04, Reg{
+			Name:     "DIV",
+			ReadOnly: 0xFF,
+			Write:    func(value byte) { got = append(got, value) },
+		})
+		io.SetValue(0x04, 0x33)
+
+		err := io.SetByte(0x04, 0x12)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, []byte{0x33})
+	})
+
+	t.Run("read only bits from read function", func(t *testing.T) {
+		var got []byte
+		current := byte(0x80)
+		io := NewIO(0x10)
+		io.Register(0x01, Reg{
+			Name:     "STAT",
+			ReadOnly: 0x87,
+			Read:     func() byte { return current },
+			Write:    func(value byte) { got = append(got, value) },
+		})
+
+		io.SetByte(0x01, 0x78)
+		current = 0x03
+		io.SetByte(0x01, 0x00)
+
+		assert.Equal(t, got, []byte{0xF8, 0x03})
+	})
+
+	t.Run("no register", func(t *testing.T) {
+		io := NewIO(0x10)
+
+		err := io.SetByte(0x02, 0x11)
+		assert.Err(t, err, false)
+		assert.Equal(t, io.Value(0x02), byte(0xFF))
+	})
+
+	t.Run("outside space", func(t *testing.T) {
+		err := NewIO(0x10).SetByte(0x10, 0x11)
+		assert.Err(t, err, true)
+	})
+}
+
+func TestIO_MMU(t *testing.T) {
+	io := NewIO(IOSize)
+	io.Register(0x46, Reg{Name: "DMA"})
+
+	mmu := &MMU{}
+	mmu.AddNamedMem("I/O", IOStart, io)
+
+	err := mmu.SetByte(0xFF46, 0xC1)
+	assert.Err(t, err, false)
+
+	got, _ := mmu.GetByte(0xFF46)
+	assert.Equal(t, got, byte(0xC1))
+}
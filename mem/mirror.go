@@ -0,0 +1,45 @@
+package mem
+
+import (
+	"fmt"
+
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// Mirror is a memory which repeats the first addresses
+// of another memory.
+type Mirror struct {
+	len uint16
+	mem Mem
+}
+
+// NewMirror creates a new Mirror with addresses from 0x0000
+// to the given length, which reads and writes the same
+// addresses of the given memory.
+func NewMirror(mem Mem, len uint16) *Mirror {
+	return &Mirror{len, mem}
+}
+
+// GetByte returns the byte at the given address of the mirrored memory.
+// If the address is outside the mirror, it returns an error.
+func (m *Mirror) GetByte(addr uint16) (byte, error) {
+	if !m.Accepts(addr) {
+		return 0, errors.E(fmt.Sprintf("address %v outside of space", addr), errors.Mem)
+	}
+	return m.mem.GetByte(addr)
+}
+
+// SetByte sets the byte at the given address of the mirrored memory.
+// If the address is outside the mirror, it returns an error.
+func (m *Mirror) SetByte(addr uint16, value byte) error {
+	if !m.Accepts(addr) {
+		return errors.E(fmt.Sprintf("address %v outside of space", addr), errors.Mem)
+	}
+	return m.mem.SetByte(addr, value)
+}
+
+// Accepts checks if an address is included in the mirror
+// and in the mirrored memory.
+func (m *Mirror) Accepts(addr uint16) bool {
+	return addr < m.len && m.mem.Accepts(addr)
+}
@@ -0,0 +1,40 @@
+package mem
+
+import (
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+func TestMirror(t *testing.T) {
+	ram := NewRAM(0x1000)
+	mirror := NewMirror(ram, 0x0800)
+
+	t.Run("write to mirror", func(t *testing.T) {
+		err := mirror.SetByte(0x0010, 0x11)
+		assert.Err(t, err, false)
+
+		got, _ := ram.GetByte(0x0010)
+		assert.Equal(t, got, byte(0x11))
+	})
+
+	t.Run("write to mirrored mem", func(t *testing.T) {
+		ram.SetByte(0x07FF, 0x22)
+
+		got, err := mirror.GetByte(0x07FF)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, byte(0x22))
+	})
+
+	t.Run("outside mirror", func(t *testing.T) {
+		_, err := mirror.GetByte(0x0800)
+		assert.Err(t, err, true)
+
+		err = mirror.SetByte(0x0800, 0x11)
+		assert.Err(t, err, true)
+	})
+
+	t.Run("mirror larger than mem", func(t *testing.T) {
+		assert.Equal(t, NewMirror(ram, 0x2000).Accepts(0x1000), false)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/lucactt/gameboy/util/errors"
+	"github.com/rs/zerolog/log"
 )
 
 // Mem represents a general purpose memory from which bytes
@@ -36,6 +37,7 @@ type Mem interface {
 //
 // This is required because the interface Mem doesn't store a start addr.
 type space struct {
+	name  string
 	start uint16
 	mem   Mem
 }
@@ -52,65 +54,230 @@ func (s *space) Accepts(addr uint16) bool {
 	return s.mem.Accepts(addr - s.start)
 }
 
+// OverlapPolicy defines what the MMU does when a memory is added
+// over the addresses of a memory added before.
+type OverlapPolicy int
+
+// Overlap policies.
+const (
+	// OverlapAllow adds the memory, which handles only the addresses
+	// not handled by the previous memories.
+	OverlapAllow OverlapPolicy = iota
+	// OverlapWarn logs a warning and adds the memory as OverlapAllow.
+	OverlapWarn
+	// OverlapReject returns an error without adding the memory.
+	OverlapReject
+)
+
+// Region is a range of addresses handled by a memory of an MMU.
+type Region struct {
+	Name  string
+	Start uint16
+	End   uint16
+	Mem   Mem
+}
+
+func (r Region) String() string {
+	return fmt.Sprintf("%04X-%04X %s", r.Start, r.End, r.Name)
+}
+
+// Size of the pages of the MMU page table.
+const (
+	pageBits = 8
+	pageSize = 1 << pageBits
+	numPages = 0x10000 / pageSize
+)
+
+// page contains the spaces which handle the addresses of a page.
+type page struct {
+	// spaces handle at least one address of the page,
+	// and are sorted by registration order.
+	spaces []*space
+	// full is true if the first space handles every address of the page,
+	// in which case Accepts does not need to be called.
+	full bool
+}
+
+// find returns the space that handles the given address
+// of the page, or nil if there is none.
+func (p *page) find(addr uint16) *space {
+	if p.full {
+		return p.spaces[0]
+	}
+
+	for _, s := range p.spaces {
+		if s.Accepts(addr) {
+			return s
+		}
+	}
+	return nil
+}
+
 // MMU represents a Memory Management Unit that wraps many
 // memories. Externally it behaves just like any memory.
 //
+// The memories are looked up with a page table built by AddMem, so
+// the result of Accepts of a memory must not change after it is added.
+//
 // It implements the Mem interface.
 type MMU struct {
-	spaces []*space
+	// Overlap defines what AddMem does when memories overlap.
+	Overlap OverlapPolicy
+	spaces  []*space
+	pages   [numPages]page
 }
 
 // GetByte returns the byte at the given address.
 // If the address is outside every wrapped memory,
-// it will return an error.
+// or the memory that accepts it fails, it will return an error.
 func (m *MMU) GetByte(addr uint16) (byte, error) {
-	for _, s := range m.spaces {
-		if s.Accepts(addr) {
-			res, err := s.GetByte(addr)
-			if err != nil {
-				// If this happens it's because of a development error, so panic is ok.
-				panic(errors.E(fmt.Sprintf("mem accepts %d, but GetByte returned error", addr), err))
-			}
-			return res, nil
-		}
+	s := m.find(addr)
+	if s == nil {
+		return 0, errors.E("no memory space accepts addr", errors.Mem)
 	}
 
-	return 0, errors.E("no memory space accepts addr", errors.Mem)
+	res, err := s.GetByte(addr)
+	if err != nil {
+		return 0, errors.E(fmt.Sprintf("mem accepts %d, but GetByte returned error", addr), err, errors.Mem)
+	}
+	return res, nil
 }
 
 // SetByte sets the byte at the given address.
 // If the address is outside every wrapped memory,
-// it will return an error.
+// or the memory that accepts it fails, it will return an error.
 func (m *MMU) SetByte(addr uint16, value byte) error {
-	for _, s := range m.spaces {
-		if s.Accepts(addr) {
-			err := s.SetByte(addr, value)
-			if err != nil {
-				panic(errors.E(fmt.Sprintf("mem accepts %d, but SetByte returned error", addr), err))
-			}
-			return nil
-		}
+	s := m.find(addr)
+	if s == nil {
+		return errors.E("no memory space accepts addr", errors.Mem)
 	}
 
-	return errors.E("no memory space accepts addr", errors.Mem)
+	if err := s.SetByte(addr, value); err != nil {
+		return errors.E(fmt.Sprintf("mem accepts %d, but SetByte returned error", addr), err, errors.Mem)
+	}
+	return nil
 }
 
 // Accepts checks if any of the underlying memories
 // accept the given address.
 func (m *MMU) Accepts(addr uint16) bool {
-	for _, s := range m.spaces {
-		if s.Accepts(addr) {
-			return true
+	return m.find(addr) != nil
+}
+
+// RegionAt returns the region which handles the given address.
+// It returns false if no memory accepts the address.
+func (m *MMU) RegionAt(addr uint16) (Region, bool) {
+	s := m.find(addr)
+	if s == nil {
+		return Region{}, false
+	}
+
+	start, end := addr, addr
+	for start > 0 && m.find(start-1) == s {
+		start--
+	}
+	for end < 0xFFFF && m.find(end+1) == s {
+		end++
+	}
+
+	return Region{Name: s.name, Start: start, End: end, Mem: s.mem}, true
+}
+
+// find returns the space that handles the given address,
+// or nil if there is none.
+func (m *MMU) find(addr uint16) *space {
+	return m.pages[addr>>pageBits].find(addr)
+}
+
+// Regions returns the ranges of addresses handled by each memory,
+// sorted by address. A memory which handles non contiguous addresses
+// has a region for each range, and the addresses covered by memories
+// added before it are excluded.
+func (m *MMU) Regions() []Region {
+	var regions []Region
+	var current *space
+
+	for addr := 0; addr <= 0xFFFF; addr++ {
+		s := m.find(uint16(addr))
+
+		switch {
+		case s == nil:
+		case s == current:
+			regions[len(regions)-1].End = uint16(addr)
+		default:
+			regions = append(regions, Region{Name: s.name, Start: uint16(addr), End: uint16(addr), Mem: s.mem})
 		}
+		current = s
 	}
-	return false
+
+	return regions
+}
+
+// AddMem adds a memory without a name to the MMU at the given address.
+// See AddNamedMem.
+func (m *MMU) AddMem(start uint16, mem Mem) error {
+	return m.AddNamedMem("", start, mem)
 }
 
-// AddMem adds a memory to the MMU at the given address.
+// AddNamedMem adds a memory to the MMU at the given address.
+// The name identifies the memory in the regions and in the errors.
 //
 // Note that if the MMU already contains a memory that "covers"
 // (even some of) the addresses of the Mem to add, that memory
-// will be the one to handle those addresses.
-func (m *MMU) AddMem(start uint16, mem Mem) {
-	m.spaces = append(m.spaces, &space{start, mem})
+// will be the one to handle those addresses. Depending on the Overlap policy,
+// the overlap can be logged or rejected with an error.
+func (m *MMU) AddNamedMem(name string, start uint16, mem Mem) error {
+	s := &space{name, start, mem}
+
+	if m.Overlap != OverlapAllow {
+		if err := m.checkOverlap(s); err != nil {
+			if m.Overlap == OverlapReject {
+				return err
+			}
+			log.Warn().Err(err).Msg("memory overlap")
+		}
+	}
+
+	m.spaces = append(m.spaces, s)
+
+	for i := range m.pages {
+		p := &m.pages[i]
+		if p.full {
+			continue
+		}
+
+		// The space is added to the page if it handles at least
+		// one address not handled by the previous spaces.
+		handled, all := false, true
+		for addr := i << pageBits; addr < (i+1)<<pageBits; addr++ {
+			accepts := s.Accepts(uint16(addr))
+			all = all && accepts
+			if accepts && p.find(uint16(addr)) == nil {
+				handled = true
+			}
+		}
+
+		if handled {
+			p.spaces = append(p.spaces, s)
+			p.full = all && len(p.spaces) == 1
+		}
+	}
+
+	return nil
+}
+
+// checkOverlap returns an error if the space accepts
+// an address already handled by another space.
+func (m *MMU) checkOverlap(s *space) error {
+	for addr := 0; addr <= 0xFFFF; addr++ {
+		if !s.Accepts(uint16(addr)) {
+			continue
+		}
+
+		if other := m.find(uint16(addr)); other != nil {
+			msg := fmt.Sprintf("mem %q at 0x%04X overlaps mem %q at 0x%04X", s.name, s.start, other.name, addr)
+			return errors.E(msg, errors.Mem)
+		}
+	}
+	return nil
 }
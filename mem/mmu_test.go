@@ -47,7 +47,7 @@ func TestMem_GetByte(t *testing.T) {
 	}{
 		{"addr in memory", 0x0001, false, 0x11, false, false},
 		{"addr not in memory", 0x1000, false, 0, true, false},
-		{"space error", 0x0001, true, 0, false, true},
+		{"space error", 0x0001, true, 0, true, false},
 	}
 
 	for _, tt := range tests {
@@ -80,7 +80,7 @@ func TestMem_SetByte(t *testing.T) {
 	}{
 		{"addr in memory", 0x0001, false, 0x11, false, false},
 		{"addr not in memory", 0x1000, false, 0, true, false},
-		{"space error", 0x0001, true, 0, true, true},
+		{"space error", 0x0001, true, 0, true, false},
 	}
 
 	for _, tt := range tests {
@@ -123,3 +123,170 @@ func TestMem_Accepts(t *testing.T) {
 		assert.Equal(t, got, false)
 	})
 }
+
+func TestMMU_AddMem(t *testing.T) {
+	mmu := &MMU{}
+	mmu.AddMem(0x0000, &TestMem{len: 0x0180, value: 0x01})
+	mmu.AddMem(0x0100, &TestMem{len: 0x0200, value: 0x02})
+	mmu.AddMem(0x0000, &TestMem{len: 0x1000, value: 0x03})
+	mmu.AddMem(0xFFFF, &TestMem{len: 0x0001, value: 0x04})
+
+	tests := []struct {
+		name string
+		addr uint16
+		want byte
+	}{
+		{"first mem", 0x0000, 0x01},
+		{"first mem, shared page", 0x017F, 0x01},
+		{"second mem, shared page", 0x0180, 0x02},
+		{"second mem", 0x02FF, 0x02},
+		{"third mem", 0x0300, 0x03},
+		{"single byte mem", 0xFFFF, 0x04},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mmu.GetByte(tt.addr)
+			assert.Err(t, err, false)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+
+	t.Run("unmapped addr in mapped page", func(t *testing.T) {
+		assert.Equal(t, mmu.Accepts(0xFFFE), false)
+	})
+}
+
+// linearMMU looks up the memories with a linear scan,
+// and is used as a baseline in the benchmarks.
+type linearMMU struct {
+	spaces []*space
+}
+
+func (m *linearMMU) AddMem(start uint16, mem Mem) error {
+	m.spaces = append(m.spaces, &space{start: start, mem: mem})
+	return nil
+}
+
+func (m *linearMMU) GetByte(addr uint16) (byte, error) {
+	for _, s := range m.spaces {
+		if s.Accepts(addr) {
+			return s.GetByte(addr)
+		}
+	}
+	return 0, errors.New("test")
+}
+
+// dmgMap adds to mmu memories laid out like the DMG memory map.
+func dmgMap(mmu interface{ AddMem(uint16, Mem) error }) {
+	mmu.AddMem(0x0000, NewRAM(0x8000)) // Cartridge ROM
+	mmu.AddMem(0x8000, NewRAM(0x2000)) // VRAM
+	mmu.AddMem(0xA000, NewRAM(0x2000)) // Cartridge RAM
+	mmu.AddMem(0xC000, NewRAM(0x2000)) // WRAM
+	mmu.AddMem(0xFE00, NewRAM(0x00A0)) // OAM
+	mmu.AddMem(0xFF0F, NewRAM(0x0001)) // IF
+	mmu.AddMem(0xFF00, NewRAM(0x0080)) // I/O
+	mmu.AddMem(0xFF80, NewRAM(0x007F)) // HRAM
+	mmu.AddMem(0xFFFF, NewRAM(0x0001)) // IE
+}
+
+// dmgAccesses are addresses accessed by a typical game: mostly ROM,
+// followed by WRAM, HRAM and I/O registers.
+var dmgAccesses = []uint16{
+	0x0150, 0x4123, 0x0151, 0xC010, 0x4124, 0xFF44, 0x0152, 0xFF85,
+	0x5000, 0xC100, 0x0153, 0x9800, 0xFF0F, 0xFFFF, 0x0154, 0xFE00,
+}
+
+func BenchmarkMMU_GetByte(b *testing.B) {
+	mmu := &MMU{}
+	dmgMap(mmu)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mmu.GetByte(dmgAccesses[i%len(dmgAccesses)])
+	}
+}
+
+func BenchmarkLinearMMU_GetByte(b *testing.B) {
+	mmu := &linearMMU{}
+	dmgMap(mmu)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mmu.GetByte(dmgAccesses[i%len(dmgAccesses)])
+	}
+}
+
+func BenchmarkMMU_AddMem(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		dmgMap(&MMU{})
+	}
+}
+
+func TestMMU_AddNamedMem(t *testing.T) {
+	tests := []struct {
+		name    string
+		overlap OverlapPolicy
+		wantErr bool
+		want    byte
+	}{
+		{"allow", OverlapAllow, false, 0x01},
+		{"warn", OverlapWarn, false, 0x01},
+		{"reject", OverlapReject, true, 0x01},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mmu := &MMU{Overlap: tt.overlap}
+			err := mmu.AddNamedMem("first", 0x0000, &TestMem{len: 0x0100, value: 0x01})
+			assert.Err(t, err, false)
+
+			err = mmu.AddNamedMem("second", 0x00FF, &TestMem{len: 0x0100, value: 0x02})
+			assert.Err(t, err, tt.wantErr)
+
+			got, _ := mmu.GetByte(0x00FF)
+			assert.Equal(t, got, tt.want)
+
+			_, ok := mmu.RegionAt(0x0100)
+			assert.Equal(t, ok, !tt.wantErr)
+		})
+	}
+
+	t.Run("no overlap", func(t *testing.T) {
+		mmu := &MMU{Overlap: OverlapReject}
+		mmu.AddNamedMem("first", 0x0000, &TestMem{len: 0x0100})
+
+		err := mmu.AddNamedMem("second", 0x0100, &TestMem{len: 0x0100})
+		assert.Err(t, err, false)
+	})
+}
+
+func TestMMU_Regions(t *testing.T) {
+	mmu := &MMU{}
+	wram := NewRAM(0x2000)
+	mmu.AddNamedMem("ROM", 0x0000, NewRAM(0x8000))
+	mmu.AddNamedMem("IF", 0xFF0F, NewRAM(0x0001))
+	mmu.AddNamedMem("WRAM", 0xC000, wram)
+	mmu.AddNamedMem("IO", 0xFF00, NewRAM(0x0080))
+
+	want := []Region{
+		{"ROM", 0x0000, 0x7FFF, mmu.spaces[0].mem},
+		{"WRAM", 0xC000, 0xDFFF, wram},
+		{"IO", 0xFF00, 0xFF0E, mmu.spaces[3].mem},
+		{"IF", 0xFF0F, 0xFF0F, mmu.spaces[1].mem},
+		{"IO", 0xFF10, 0xFF7F, mmu.spaces[3].mem},
+	}
+	assert.Equal(t, mmu.Regions(), want)
+
+	t.Run("region at addr", func(t *testing.T) {
+		got, ok := mmu.RegionAt(0xC123)
+		assert.Equal(t, ok, true)
+		assert.Equal(t, got.Name, "WRAM")
+		assert.Equal(t, got.String(), "C000-DFFF WRAM")
+	})
+
+	t.Run("unmapped addr", func(t *testing.T) {
+		_, ok := mmu.RegionAt(0xE000)
+		assert.Equal(t, ok, false)
+	})
+}
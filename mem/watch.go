@@ -0,0 +1,184 @@
+package mem
+
+// Access is the type of a memory access.
+type Access byte
+
+// Types of memory access. They can be combined to watch
+// both reads and writes.
+const (
+	Read Access = 1 << iota
+	Write
+
+	ReadWrite = Read | Write
+)
+
+// String returns the name of the access type.
+func (a Access) String() string {
+	switch a {
+	case Read:
+		return "read"
+	case Write:
+		return "write"
+	case ReadWrite:
+		return "read/write"
+	default:
+		return "none"
+	}
+}
+
+// Event describes a memory access that matched a watchpoint.
+type Event struct {
+	Addr   uint16
+	Value  byte
+	Access Access
+	// PC is the value of the program counter when the access happened,
+	// or 0 if the Watch has no PC function.
+	PC uint16
+}
+
+// Hook is called on the accesses that match a watchpoint.
+// If it returns true, the Watch asks the CPU to pause.
+type Hook func(e Event) bool
+
+// Watchpoint describes the accesses that run a hook.
+type Watchpoint struct {
+	// Start and End are the first and last watched addresses.
+	Start, End uint16
+	// Access is the type of the watched accesses.
+	Access Access
+	// If MatchValue is true, the hook runs only if
+	// the accessed byte is equal to Value.
+	MatchValue bool
+	Value      byte
+
+	Hook Hook
+}
+
+// watchpoint is a Watchpoint added to a Watch, with its id.
+type watchpoint struct {
+	Watchpoint
+	id      int
+	removed bool
+}
+
+// matches checks if an access matches the watchpoint.
+func (wp *Watchpoint) matches(e Event) bool {
+	return wp.Access&e.Access != 0 &&
+		e.Addr >= wp.Start && e.Addr <= wp.End &&
+		(!wp.MatchValue || e.Value == wp.Value)
+}
+
+// Watch is a memory which runs the hooks of its watchpoints
+// on the accesses to another memory. The hooks run after
+// the access completes, and only if it succeeds.
+//
+// Without watchpoints, the accesses are forwarded to the
+// wrapped memory with no other work.
+// Note that the instruction fetches and the reads of the tracer
+// are accesses too, so they can run the read hooks.
+//
+// It implements the Mem interface.
+type Watch struct {
+	// PC returns the current value of the program counter.
+	// If nil, the events have PC 0.
+	PC func() uint16
+	// Pause is called when a hook asks to pause.
+	// It's usually set to the Pause method of the CPU.
+	Pause func()
+
+	mem    Mem
+	nextID int
+	// points are the watchpoints, in the order they were added.
+	points []*watchpoint
+	// pages marks the pages of 256 bytes which contain
+	// at least a watched address.
+	pages [256]bool
+}
+
+// NewWatch creates a new Watch for the given memory,
+// with no watchpoints.
+func NewWatch(mem Mem) *Watch {
+	return &Watch{mem: mem}
+}
+
+// Add adds a watchpoint, and returns an id that can be
+// used to remove it. The hooks run in the order their
+// watchpoints were added.
+func (w *Watch) Add(wp Watchpoint) int {
+	w.nextID++
+	w.points = append(w.points, &watchpoint{Watchpoint: wp, id: w.nextID})
+	w.updatePages()
+	return w.nextID
+}
+
+// Remove removes the watchpoint with the given id.
+// Unknown ids are ignored.
+func (w *Watch) Remove(id int) {
+	for i, wp := range w.points {
+		if wp.id == id {
+			wp.removed = true
+			w.points = append(w.points[:i], w.points[i+1:]...)
+			break
+		}
+	}
+	w.updatePages()
+}
+
+// updatePages marks the pages that contain the addresses of the watchpoints.
+func (w *Watch) updatePages() {
+	w.pages = [256]bool{}
+	for _, wp := range w.points {
+		for p := int(wp.Start >> 8); p <= int(wp.End>>8); p++ {
+			w.pages[p] = true
+		}
+	}
+}
+
+// GetByte returns the byte at the given address of the watched memory,
+// then runs the hooks of the matching watchpoints.
+func (w *Watch) GetByte(addr uint16) (byte, error) {
+	value, err := w.mem.GetByte(addr)
+	if err == nil && w.pages[addr>>8] {
+		w.run(addr, value, Read)
+	}
+	return value, err
+}
+
+// SetByte sets the byte at the given address of the watched memory,
+// then runs the hooks of the matching watchpoints.
+func (w *Watch) SetByte(addr uint16, value byte) error {
+	err := w.mem.SetByte(addr, value)
+	if err == nil && w.pages[addr>>8] {
+		w.run(addr, value, Write)
+	}
+	return err
+}
+
+// Accepts checks if an address is included in the watched memory.
+func (w *Watch) Accepts(addr uint16) bool {
+	return w.mem.Accepts(addr)
+}
+
+// run calls the hooks of the watchpoints that match the access,
+// and asks to pause if any of them returns true.
+func (w *Watch) run(addr uint16, value byte, access Access) {
+	e := Event{Addr: addr, Value: value, Access: access}
+	if w.PC != nil {
+		e.PC = w.PC()
+	}
+
+	// The hooks can add or remove watchpoints, so they are run on
+	// a copy of the current ones, skipping the removed ones.
+	points := append([]*watchpoint(nil), w.points...)
+
+	pause := false
+	for _, wp := range points {
+		if !wp.removed && wp.matches(e) && wp.Hook(e) {
+			pause = true
+		}
+	}
+
+	if pause && w.Pause != nil {
+		w.Pause()
+	}
+}
@@ -0,0 +1,151 @@
+package mem
+
+import (
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+func TestWatch_hooks(t *testing.T) {
+	tests := []struct {
+		name   string
+		wp     Watchpoint
+		write  bool
+		addr   uint16
+		value  byte
+		wantOK bool
+	}{
+		{"read in range", Watchpoint{Start: 0xC000, End: 0xC0FF, Access: Read}, false, 0xC080, 0x00, true},
+		{"read outside range", Watchpoint{Start: 0xC000, End: 0xC0FF, Access: Read}, false, 0xC100, 0x00, false},
+		{"write on read watchpoint", Watchpoint{Start: 0xC000, End: 0xC0FF, Access: Read}, true, 0xC080, 0x01, false},
+		{"write in range", Watchpoint{Start: 0xC000, End: 0xC0FF, Access: Write}, true, 0xC0FF, 0x01, true},
+		{"read/write", Watchpoint{Start: 0xC000, End: 0xC000, Access: ReadWrite}, true, 0xC000, 0x01, true},
+		{"matching value", Watchpoint{Start: 0xC000, End: 0xC000, Access: Write, MatchValue: true, Value: 0x42}, true, 0xC000, 0x42, true},
+		{"other value", Watchpoint{Start: 0xC000, End: 0xC000, Access: Write, MatchValue: true, Value: 0x42}, true, 0xC000, 0x41, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWatch(NewRAM(0xFFFF))
+			w.PC = func() uint16 { return 0x0150 }
+
+			var got []Event
+			tt.wp.Hook = func(e Event) bool {
+				got = append(got, e)
+				return false
+			}
+			w.Add(tt.wp)
+
+			var err error
+			access := Read
+			if tt.write {
+				access = Write
+				err = w.SetByte(tt.addr, tt.value)
+			} else {
+				_, err = w.GetByte(tt.addr)
+			}
+			assert.Err(t, err, false)
+
+			var want []Event
+			if tt.wantOK {
+				want = []Event{{Addr: tt.addr, Value: tt.value, Access: access, PC: 0x0150}}
+			}
+			assert.Equal(t, got, want)
+		})
+	}
+}
+
+func TestWatch_Pause(t *testing.T) {
+	w := NewWatch(NewRAM(0xFFFF))
+	paused := 0
+	w.Pause = func() { paused++ }
+
+	w.Add(Watchpoint{Start: 0x0000, End: 0xFFFF, Access: Write, Hook: func(e Event) bool { return false }})
+	id := w.Add(Watchpoint{Start: 0xFF80, End: 0xFFFE, Access: Write, Hook: func(e Event) bool { return true }})
+
+	w.SetByte(0xC000, 0x01)
+	assert.Equal(t, paused, 0)
+
+	w.SetByte(0xFF80, 0x01)
+	assert.Equal(t, paused, 1)
+
+	t.Run("removed watchpoint", func(t *testing.T) {
+		w.Remove(id)
+		w.SetByte(0xFF80, 0x01)
+		assert.Equal(t, paused, 1)
+	})
+}
+
+func TestWatch_Remove(t *testing.T) {
+	w := NewWatch(NewRAM(0xFFFF))
+	var calls []int
+
+	var first int
+	first = w.Add(Watchpoint{Start: 0xC000, End: 0xC000, Access: Write, Hook: func(e Event) bool {
+		calls = append(calls, 1)
+		w.Remove(first)
+		return false
+	}})
+	w.Add(Watchpoint{Start: 0xC000, End: 0xC000, Access: Write, Hook: func(e Event) bool {
+		calls = append(calls, 2)
+		return false
+	}})
+	w.Add(Watchpoint{Start: 0xC000, End: 0xC000, Access: Write, Hook: func(e Event) bool {
+		calls = append(calls, 3)
+		return false
+	}})
+
+	w.SetByte(0xC000, 0x01)
+	assert.Equal(t, calls, []int{1, 2, 3})
+
+	t.Run("removed by its hook", func(t *testing.T) {
+		calls = nil
+		w.SetByte(0xC000, 0x01)
+		assert.Equal(t, calls, []int{2, 3})
+	})
+}
+
+func TestWatch_RemoveOther(t *testing.T) {
+	w := NewWatch(NewRAM(0xFFFF))
+	var calls []int
+
+	var second int
+	w.Add(Watchpoint{Start: 0xC000, End: 0xC000, Access: Write, Hook: func(e Event) bool {
+		calls = append(calls, 1)
+		w.Remove(second)
+		return false
+	}})
+	second = w.Add(Watchpoint{Start: 0xC000, End: 0xC000, Access: Write, Hook: func(e Event) bool {
+		calls = append(calls, 2)
+		return false
+	}})
+
+	w.SetByte(0xC000, 0x01)
+	assert.Equal(t, calls, []int{1})
+}
+
+func TestWatch_failedAccess(t *testing.T) {
+	w := NewWatch(&TestMem{len: 0x1000, forceErr: true})
+	called := false
+	w.Add(Watchpoint{Start: 0x0000, End: 0x0FFF, Access: ReadWrite, Hook: func(e Event) bool {
+		called = true
+		return true
+	}})
+
+	_, err := w.GetByte(0x0000)
+	assert.Err(t, err, true)
+	err = w.SetByte(0x0000, 0x01)
+	assert.Err(t, err, true)
+	assert.Equal(t, called, false)
+}
+
+func BenchmarkWatch_GetByte(b *testing.B) {
+	mmu := &MMU{}
+	dmgMap(mmu)
+	w := NewWatch(mmu)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.GetByte(dmgAccesses[i%len(dmgAccesses)])
+	}
+}
@@ -0,0 +1,43 @@
+package romtest
+
+import (
+	"regexp"
+	"strings"
+)
+
+// BlarggResult is the result of a Blargg test ROM.
+type BlarggResult struct {
+	// Done is true if the ROM printed its final result.
+	Done bool
+
+	// Passed is true if all the tests passed.
+	Passed bool
+
+	// SubTests contains the result of each sub-test printed
+	// by the ROMs that run many of them, such as cpu_instrs.
+	// The value is "ok" for the passed sub-tests, or the failure code.
+	SubTests map[string]string
+}
+
+// subTestRegexp matches the sub-test results printed by
+// the combined ROMs, such as "01:ok" or "02:05".
+var subTestRegexp = regexp.MustCompile(`(\d\d):(ok|\d+)`)
+
+// ParseBlargg parses the text printed by a Blargg
+// test ROM through the serial port.
+func ParseBlargg(out string) BlarggResult {
+	res := BlarggResult{SubTests: make(map[string]string)}
+
+	for _, m := range subTestRegexp.FindAllStringSubmatch(out, -1) {
+		res.SubTests[m[1]] = m[2]
+	}
+
+	switch {
+	case strings.Contains(out, "Passed"):
+		res.Done = true
+		res.Passed = true
+	case strings.Contains(out, "Failed"):
+		res.Done = true
+	}
+	return res
+}
@@ -0,0 +1,94 @@
+package romtest
+
+import (
+	"flag"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+// blarggCycles is the number of cycles after which a Blargg ROM times out.
+var blarggCycles = flag.Int("blargg.cycles", 300000000, "cycles after which a Blargg test ROM times out")
+
+// blarggDirs are the directories of testdata that contain the Blargg ROMs.
+var blarggDirs = []string{"cpu_instrs", "instr_timing", "mem_timing"}
+
+func TestBlargg(t *testing.T) {
+	var roms []string
+	for _, dir := range blarggDirs {
+		matches, _ := filepath.Glob(filepath.Join("testdata", "blargg", dir, "*.gb"))
+		matches2, _ := filepath.Glob(filepath.Join("testdata", "blargg", dir, "individual", "*.gb"))
+		roms = append(roms, matches...)
+		roms = append(roms, matches2...)
+	}
+	sort.Strings(roms)
+
+	if len(roms) == 0 {
+		t.Skip("no Blargg ROMs in testdata/blargg")
+	}
+
+	for _, rom := range roms {
+		rom := rom
+		name, _ := filepath.Rel(filepath.Join("testdata", "blargg"), rom)
+
+		t.Run(name, func(t *testing.T) {
+			s, err := Load(rom)
+			assert.Err(t, err, false)
+
+			// The output is parsed again only when it changes.
+			var res BlarggResult
+			parsedLen := -1
+			finished, err := s.Run(*blarggCycles, func() bool {
+				if s.Serial.Len() != parsedLen {
+					parsedLen = s.Serial.Len()
+					res = ParseBlargg(s.Serial.String())
+				}
+				return res.Done
+			})
+			if err != nil {
+				t.Fatalf("%v\noutput:\n%s", err, s.Serial)
+			}
+			if !finished {
+				t.Fatalf("timed out after %d cycles\noutput:\n%s", *blarggCycles, s.Serial)
+			}
+
+			for n, r := range res.SubTests {
+				r := r
+				t.Run(n, func(t *testing.T) {
+					if r != "ok" {
+						t.Errorf("failed with code %s", r)
+					}
+				})
+			}
+
+			if !res.Passed {
+				t.Errorf("failed\noutput:\n%s", s.Serial)
+			}
+		})
+	}
+}
+
+func TestParseBlargg(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want BlarggResult
+	}{
+		{"running", "01-special\n\n", BlarggResult{false, false, map[string]string{}}},
+		{"passed", "01-special\n\n\nPassed\n", BlarggResult{true, true, map[string]string{}}},
+		{"failed", "01-special\n\n\nFailed #6\n", BlarggResult{true, false, map[string]string{}}},
+		{
+			"combined",
+			"cpu_instrs\n\n01:ok  02:04  \n\nFailed 1 tests",
+			BlarggResult{true, false, map[string]string{"01": "ok", "02": "04"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, ParseBlargg(tt.out), tt.want)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package romtest
+
+import (
+	"github.com/lucactt/gameboy/cpu"
+)
+
+// MooneyeResult is the result of a Mooneye test ROM.
+type MooneyeResult string
+
+// Mooneye test results.
+const (
+	MooneyeTimeout MooneyeResult = "timeout"
+	MooneyePassed  MooneyeResult = "passed"
+	MooneyeFailed  MooneyeResult = "failed"
+	// MooneyeUnknown means that the registers contain neither
+	// the success nor the failure signature.
+	MooneyeUnknown MooneyeResult = "unknown"
+)
+
+// mooneyeDebug is the opcode of LD B,B, which the Mooneye ROMs
+// run after loading the result in the registers.
+const mooneyeDebug byte = 0x40
+
+// Register values loaded by the Mooneye ROMs, in B, C, D, E, H, L order.
+var (
+	mooneyePassRegs = [6]byte{3, 5, 8, 13, 21, 34}
+	mooneyeFailRegs = [6]byte{0x42, 0x42, 0x42, 0x42, 0x42, 0x42}
+)
+
+// RunMooneye runs a Mooneye test ROM until it runs LD B,B, or
+// until the given number of cycles is reached, and returns the result
+// found in the registers.
+func (s *System) RunMooneye(maxCycles int) (MooneyeResult, error) {
+	res := MooneyeTimeout
+	_, err := s.Run(maxCycles, func() bool {
+		if !s.ranOpCode(mooneyeDebug) {
+			return false
+		}
+
+		res = ParseMooneye(s.CPU.Regs)
+		return true
+	})
+	return res, err
+}
+
+// ParseMooneye returns the result of a Mooneye test ROM from the registers.
+func ParseMooneye(regs *cpu.Regs) MooneyeResult {
+	got := [6]byte{
+		regs.BC.Hi(), regs.BC.Lo(),
+		regs.DE.Hi(), regs.DE.Lo(),
+		regs.HL.Hi(), regs.HL.Lo(),
+	}
+
+	switch got {
+	case mooneyePassRegs:
+		return MooneyePassed
+	case mooneyeFailRegs:
+		return MooneyeFailed
+	default:
+		return MooneyeUnknown
+	}
+}
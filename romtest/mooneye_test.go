@@ -0,0 +1,112 @@
+package romtest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lucactt/gameboy/cpu"
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+// mooneyeCycles is the number of cycles after which a Mooneye ROM times out.
+var mooneyeCycles = flag.Int("mooneye.cycles", 50000000, "cycles after which a Mooneye test ROM times out")
+
+func TestMooneye(t *testing.T) {
+	var roms []string
+	root := filepath.Join("testdata", "mooneye")
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && strings.HasSuffix(path, ".gb") {
+			roms = append(roms, path)
+		}
+		return nil
+	})
+	sort.Strings(roms)
+
+	if len(roms) == 0 {
+		t.Skip("no Mooneye ROMs in testdata/mooneye")
+	}
+
+	for _, rom := range roms {
+		rom := rom
+		name, _ := filepath.Rel(root, rom)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := Load(rom)
+			assert.Err(t, err, false)
+
+			res, err := s.RunMooneye(*mooneyeCycles)
+			assert.Err(t, err, false)
+			if res != MooneyePassed {
+				t.Errorf("got %s, want %s", res, MooneyePassed)
+			}
+		})
+	}
+}
+
+func TestSystem_RunMooneye(t *testing.T) {
+	tests := []struct {
+		name string
+		regs [6]byte
+		want MooneyeResult
+	}{
+		{"passed", mooneyePassRegs, MooneyePassed},
+		{"failed", mooneyeFailRegs, MooneyeFailed},
+		{"unknown", [6]byte{0x42, 5, 8, 13, 21, 34}, MooneyeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, remove := writeROM(t, []byte{
+				0x06, tt.regs[0], // LD B,d8
+				0x0E, tt.regs[1], // LD C,d8
+				0x16, tt.regs[2], // LD D,d8
+				0x1E, tt.regs[3], // LD E,d8
+				0x26, tt.regs[4], // LD H,d8
+				0x2E, tt.regs[5], // LD L,d8
+				mooneyeDebug,
+				0x18, 0xFE, // JR -2
+			})
+			defer remove()
+
+			s, err := Load(path)
+			assert.Err(t, err, false)
+
+			got, err := s.RunMooneye(1000)
+			assert.Err(t, err, false)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+
+	t.Run("timeout", func(t *testing.T) {
+		path, remove := writeROM(t, []byte{0x18, 0xFE})
+		defer remove()
+
+		s, err := Load(path)
+		assert.Err(t, err, false)
+
+		got, err := s.RunMooneye(1000)
+		assert.Err(t, err, false)
+		assert.Equal(t, got, MooneyeTimeout)
+	})
+}
+
+func TestParseMooneye(t *testing.T) {
+	regs := cpu.NewRegs()
+	assert.Equal(t, ParseMooneye(regs), MooneyeUnknown)
+
+	regs.BC.Set(0x4242)
+	regs.DE.Set(0x4242)
+	regs.HL.Set(0x4242)
+	assert.Equal(t, ParseMooneye(regs), MooneyeFailed)
+
+	regs.BC.Set(0x0305)
+	regs.DE.Set(0x080D)
+	regs.HL.Set(0x1522)
+	assert.Equal(t, ParseMooneye(regs), MooneyePassed)
+}
@@ -0,0 +1,66 @@
+// Package romtest runs test ROMs, such as the Blargg and
+// Mooneye test suites, to verify the emulator correctness.
+package romtest
+
+import (
+	"bytes"
+
+	"github.com/lucactt/gameboy/cart"
+	"github.com/lucactt/gameboy/cpu"
+	"github.com/lucactt/gameboy/system"
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// System is a GameBoy that runs a test ROM and
+// captures what it prints through the serial port.
+type System struct {
+	CPU    *cpu.CPU
+	Cart   *cart.Cart
+	Serial *bytes.Buffer
+
+	// lastPC is the value of PC before the last tick.
+	lastPC uint16
+}
+
+// Load creates a new system with the ROM at the given path.
+func Load(path string) (*System, error) {
+	out := &bytes.Buffer{}
+	gb, err := system.Open(path, out)
+	if err != nil {
+		return nil, errors.E("load test rom failed", err)
+	}
+
+	return &System{CPU: gb.CPU, Cart: gb.Cart, Serial: out}, nil
+}
+
+// Run runs the system until done returns true, which is checked after
+// each instruction, or until the given number of cycles is reached.
+// It returns false if the cycles run out.
+func (s *System) Run(maxCycles int, done func() bool) (bool, error) {
+	for cycles := 0; cycles < maxCycles; {
+		s.lastPC = s.CPU.Regs.PC.HiLo()
+		n, err := s.CPU.Tick()
+		if err != nil {
+			return false, errors.E("run test rom failed", err)
+		}
+		cycles += n
+
+		if done() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ranOpCode returns true if the last tick ran a one byte
+// instruction with the given opcode.
+func (s *System) ranOpCode(opCode byte) bool {
+	// If the CPU is halted or an interrupt has been serviced,
+	// PC is not incremented by one.
+	if s.CPU.Regs.PC.HiLo() != s.lastPC+1 {
+		return false
+	}
+
+	got, err := s.CPU.Mem.GetByte(s.lastPC)
+	return err == nil && got == opCode
+}
@@ -0,0 +1,86 @@
+package romtest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+// writeROM writes a 32KB ROM with the given code at the entry point to
+// a temporary file, and returns its path and a function that removes it.
+func writeROM(t *testing.T, code []byte) (string, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "romtest")
+	assert.Err(t, err, false)
+
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0100:], code)
+
+	path := filepath.Join(dir, "test.gb")
+	assert.Err(t, ioutil.WriteFile(path, rom, 0644), false)
+	return path, func() { os.RemoveAll(dir) }
+}
+
+// printCode returns the code that prints the text through the serial port.
+func printCode(text string) []byte {
+	var code []byte
+	for _, c := range []byte(text) {
+		code = append(code,
+			0x3E, c, // LD A,c
+			0xE0, 0x01, // LDH (SB),A
+			0x3E, 0x81, // LD A,0x81
+			0xE0, 0x02, // LDH (SC),A
+		)
+	}
+	return code
+}
+
+func TestSystem_Run(t *testing.T) {
+	t.Run("done", func(t *testing.T) {
+		code := append(printCode("Passed"), 0x18, 0xFE) // JR -2
+		path, remove := writeROM(t, code)
+		defer remove()
+
+		s, err := Load(path)
+		assert.Err(t, err, false)
+
+		finished, err := s.Run(100000, func() bool {
+			return ParseBlargg(s.Serial.String()).Done
+		})
+		assert.Err(t, err, false)
+		assert.Equal(t, finished, true)
+		assert.Equal(t, s.Serial.String(), "Passed")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		path, remove := writeROM(t, []byte{0x18, 0xFE})
+		defer remove()
+
+		s, err := Load(path)
+		assert.Err(t, err, false)
+
+		finished, err := s.Run(1000, func() bool { return false })
+		assert.Err(t, err, false)
+		assert.Equal(t, finished, false)
+	})
+
+	t.Run("CPU fault", func(t *testing.T) {
+		path, remove := writeROM(t, []byte{0xD3})
+		defer remove()
+
+		s, err := Load(path)
+		assert.Err(t, err, false)
+
+		_, err = s.Run(1000, func() bool { return false })
+		assert.Err(t, err, true)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	_, err := Load(filepath.Join("testdata", "missing.gb"))
+	assert.Err(t, err, true)
+}
@@ -0,0 +1,87 @@
+// Package serial implements a minimal GameBoy serial port,
+// with no link cable connected.
+package serial
+
+import (
+	"io"
+
+	"github.com/lucactt/gameboy/cpu"
+	"github.com/lucactt/gameboy/mem"
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// Addresses of the serial registers.
+const (
+	SBAddr uint16 = 0xFF01 // Serial transfer data
+	SCAddr uint16 = 0xFF02 // Serial transfer control
+)
+
+const (
+	// scStart is the bit of SC that starts a transfer.
+	scStart byte = 0x80
+
+	// scInternalClock is the bit of SC that selects the internal clock.
+	scInternalClock byte = 0x01
+
+	// scUnused are the bits of SC that are unused and always read as 1.
+	scUnused byte = 0x7E
+)
+
+// Serial is the serial port. As no other device is connected,
+// each transfer completes immediately and receives 0xFF.
+//
+// Its SB and SC registers should be registered in
+// the mem.IO of the I/O registers.
+type Serial struct {
+	sb, sc     byte
+	out        io.Writer
+	interrupts *cpu.Interrupts
+	err        error
+}
+
+// New creates a new serial port that writes the transferred
+// bytes to out and requests the serial interrupt when a transfer completes.
+func New(out io.Writer, interrupts *cpu.Interrupts) *Serial {
+	return &Serial{out: out, interrupts: interrupts}
+}
+
+// SB returns the serial transfer data register.
+func (s *Serial) SB() mem.Reg {
+	return mem.Reg{
+		Name:  "SB",
+		Read:  func() byte { return s.sb },
+		Write: func(value byte) { s.sb = value },
+	}
+}
+
+// SC returns the serial transfer control register. Setting its start
+// bit with the internal clock selected transfers the byte in SB.
+func (s *Serial) SC() mem.Reg {
+	return mem.Reg{
+		Name:   "SC",
+		Unused: scUnused,
+		Read:   func() byte { return s.sc },
+		Write: func(value byte) {
+			s.sc = value
+			if s.sc&scStart != 0 && s.sc&scInternalClock != 0 {
+				s.transfer()
+			}
+		},
+	}
+}
+
+// Err returns the first error returned by the output writer, if any.
+func (s *Serial) Err() error {
+	return s.err
+}
+
+// transfer sends the byte in SB, and receives 0xFF as no device is connected.
+func (s *Serial) transfer() {
+	if _, err := s.out.Write([]byte{s.sb}); err != nil && s.err == nil {
+		s.err = errors.E("write serial output failed", err, errors.Mem)
+	}
+
+	s.sb = 0xFF
+	s.sc &^= scStart
+	s.interrupts.Request(cpu.Serial)
+}
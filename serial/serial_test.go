@@ -0,0 +1,72 @@
+package serial
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/lucactt/gameboy/cpu"
+	"github.com/lucactt/gameboy/mem"
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+// Addresses of SB and SC in the IO created by newIO.
+const (
+	sbAddr uint16 = 0x0000
+	scAddr uint16 = 0x0001
+)
+
+// newIO returns an IO with the registers of the serial port.
+func newIO(s *Serial) *mem.IO {
+	io := mem.NewIO(SCAddr - SBAddr + 1)
+	io.Register(sbAddr, s.SB())
+	io.Register(scAddr, s.SC())
+	return io
+}
+
+// errWriter is a writer that always fails.
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("test")
+}
+
+func TestSerial_SC(t *testing.T) {
+	t.Run("transfer", func(t *testing.T) {
+		var out bytes.Buffer
+		interrupts := cpu.NewInterrupts()
+		interrupts.IE().Write(0xFF)
+		io := newIO(New(&out, interrupts))
+
+		io.SetByte(sbAddr, 'A')
+		err := io.SetByte(scAddr, 0x81)
+		assert.Err(t, err, false)
+
+		sb, _ := io.GetByte(sbAddr)
+		sc, _ := io.GetByte(scAddr)
+		assert.Equal(t, out.String(), "A")
+		assert.Equal(t, sb, byte(0xFF))
+		assert.Equal(t, sc, byte(0x7F))
+		assert.Equal(t, interrupts.Pending(), true)
+	})
+
+	t.Run("external clock", func(t *testing.T) {
+		var out bytes.Buffer
+		io := newIO(New(&out, cpu.NewInterrupts()))
+
+		io.SetByte(sbAddr, 'A')
+		io.SetByte(scAddr, 0x80)
+
+		sc, _ := io.GetByte(scAddr)
+		assert.Equal(t, out.Len(), 0)
+		assert.Equal(t, sc, byte(0xFE))
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		s := New(errWriter{}, cpu.NewInterrupts())
+		io := newIO(s)
+
+		io.SetByte(scAddr, 0x81)
+		assert.Err(t, s.Err(), true)
+	})
+}
@@ -0,0 +1,62 @@
+// Package system connects the GameBoy components
+// into a complete system, ready to run a cartridge.
+package system
+
+import (
+	"io"
+
+	"github.com/lucactt/gameboy/cart"
+	"github.com/lucactt/gameboy/cpu"
+	"github.com/lucactt/gameboy/mem"
+	"github.com/lucactt/gameboy/serial"
+	"github.com/lucactt/gameboy/util/errors"
+)
+
+// GameBoy is a DMG with a cartridge inserted.
+type GameBoy struct {
+	CPU    *cpu.CPU
+	MMU    *mem.MMU
+	Cart   *cart.Cart
+	Serial *serial.Serial
+}
+
+// Open creates a new GameBoy with the cartridge at the given path.
+// The bytes transferred through the serial port are written to serialOut.
+func Open(path string, serialOut io.Writer, opts ...cart.Option) (*GameBoy, error) {
+	c, err := cart.Open(path, opts...)
+	if err != nil {
+		return nil, errors.E("open cartridge failed", err)
+	}
+	return New(c, serialOut)
+}
+
+// New creates a new GameBoy with the given cartridge, and maps the
+// cartridge, the interrupt registers and the serial port in its memory.
+// The bytes transferred through the serial port are written to serialOut.
+func New(c *cart.Cart, serialOut io.Writer) (*GameBoy, error) {
+	mmu := &mem.MMU{Overlap: mem.OverlapReject}
+	gb := &GameBoy{CPU: cpu.New(mmu), MMU: mmu, Cart: c}
+	gb.Serial = serial.New(serialOut, gb.CPU.Interrupts)
+
+	ioRegs, ie := mem.NewIO(mem.IOSize), mem.NewIO(1)
+	regs := []struct {
+		io   *mem.IO
+		addr uint16
+		reg  mem.Reg
+	}{
+		{ioRegs, cpu.IFAddr - mem.IOStart, gb.CPU.Interrupts.IF()},
+		{ioRegs, serial.SBAddr - mem.IOStart, gb.Serial.SB()},
+		{ioRegs, serial.SCAddr - mem.IOStart, gb.Serial.SC()},
+		{ie, 0x0000, gb.CPU.Interrupts.IE()},
+	}
+	for _, r := range regs {
+		if err := r.io.Register(r.addr, r.reg); err != nil {
+			return nil, errors.E("register i/o failed", err)
+		}
+	}
+
+	if err := mem.MapDMG(mmu, mem.DMG, c, ioRegs, ie); err != nil {
+		return nil, errors.E("map memory failed", err)
+	}
+	return gb, nil
+}
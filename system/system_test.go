@@ -0,0 +1,33 @@
+package system
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lucactt/gameboy/cart"
+	"github.com/lucactt/gameboy/util/assert"
+)
+
+func TestNew(t *testing.T) {
+	c, err := cart.NewCart(make([]byte, 0x8000), cart.WithValidation(cart.ValidateIgnore))
+	assert.Err(t, err, false)
+
+	var out bytes.Buffer
+	gb, err := New(c, &out)
+	assert.Err(t, err, false)
+
+	t.Run("serial", func(t *testing.T) {
+		gb.MMU.SetByte(0xFF01, 'A')
+		gb.MMU.SetByte(0xFF02, 0x81)
+		assert.Equal(t, out.String(), "A")
+
+		// The transfer requests the serial interrupt.
+		got, _ := gb.MMU.GetByte(0xFF0F)
+		assert.Equal(t, got, byte(0xE8))
+	})
+
+	t.Run("IE", func(t *testing.T) {
+		gb.MMU.SetByte(0xFFFF, 0x08)
+		assert.Equal(t, gb.CPU.Interrupts.Pending(), true)
+	})
+}
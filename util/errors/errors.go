@@ -12,9 +12,10 @@ type ErrComponent string
 
 // Components where errors can be originated from.
 const (
-	Mem  ErrComponent = "memory"
-	Cart ErrComponent = "cartridge"
-	CPU  ErrComponent = "CPU"
+	Mem    ErrComponent = "memory"
+	Cart   ErrComponent = "cartridge"
+	CPU    ErrComponent = "CPU"
+	Disasm ErrComponent = "disassembler"
 )
 
 // Error is a wrapper for an error value with added context.